@@ -0,0 +1,278 @@
+package docs
+
+import (
+	"html"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/espal-digital-development/espal-store-synthesizer/packages"
+	"github.com/espal-digital-development/system/permissions"
+	"github.com/juju/errors"
+)
+
+const (
+	// FormatMarkdown writes the documentation as Markdown.
+	FormatMarkdown = "markdown"
+	// FormatHTML writes the documentation as HTML pages.
+	FormatHTML = "html"
+)
+
+// Docs package object.
+type Docs struct {
+	path   string
+	format string
+}
+
+// document is a page of the documentation, which is rendered to either format.
+type document struct {
+	title    string
+	blocks   []*block
+	fileName string
+}
+
+// block is a part of a document. Only one of its fields is set.
+type block struct {
+	heading   string
+	paragraph string
+	table     *table
+	code      []string
+	links     []*link
+}
+
+// link to another page of the documentation.
+type link struct {
+	name   string
+	target string
+}
+
+// table has a header row and data rows, of which the code columns are rendered as code.
+type table struct {
+	header      []string
+	rows        [][]string
+	codeColumns map[int]bool
+}
+
+// Build writes a page per package with its entities, columns and Store, and an index linking to them.
+func (d *Docs) Build(pkgs []*packages.Package) error {
+	if err := os.MkdirAll(d.path, permissions.UserReadWriteExecute); err != nil {
+		return errors.Trace(err)
+	}
+	index := &document{title: "Stores", fileName: "index"}
+	links := &block{}
+	for _, pkg := range pkgs {
+		page := packageDocument(pkg)
+		if err := d.write(page); err != nil {
+			return errors.Trace(err)
+		}
+		links.links = append(links.links, &link{name: pkg.ImportPath(), target: page.fileName + d.extension()})
+	}
+	index.blocks = append(index.blocks, links)
+	return errors.Trace(d.write(index))
+}
+
+func (d *Docs) extension() string {
+	if d.format == FormatHTML {
+		return ".html"
+	}
+	return ".md"
+}
+
+func (d *Docs) write(doc *document) error {
+	var output string
+	if d.format == FormatHTML {
+		output = renderHTML(doc)
+	} else {
+		output = renderMarkdown(doc)
+	}
+	return errors.Trace(ioutil.WriteFile(d.path+"/"+doc.fileName+d.extension(), []byte(output),
+		permissions.UserReadWrite))
+}
+
+// packageDocument returns the data dictionary of the package.
+func packageDocument(pkg *packages.Package) *document {
+	store := pkg.Store()
+	doc := &document{
+		title:    "Package " + pkg.Name(),
+		fileName: fileName(pkg),
+	}
+	doc.blocks = append(doc.blocks, &block{paragraph: "Import path: " + pkg.ImportPath()})
+	for _, entity := range pkg.AllEntities() {
+		doc.blocks = append(doc.blocks, entityBlocks(entity)...)
+	}
+
+	doc.blocks = append(doc.blocks, &block{heading: "Store " + store.StructName()})
+	if store.IsContextAware() {
+		doc.blocks = append(doc.blocks, &block{paragraph: "The database methods have context.Context variants."})
+	}
+	if len(store.Services()) > 0 {
+		services := &table{header: []string{"Service", "Type"}, codeColumns: map[int]bool{0: true, 1: true}}
+		for _, service := range store.Services() {
+			services.rows = append(services.rows, []string{service.Name(), service.Type()})
+		}
+		doc.blocks = append(doc.blocks, &block{table: services})
+	}
+	signatures := []string{}
+	for _, method := range store.InterfaceMethods() {
+		signatures = append(signatures, store.MethodSignature(method))
+	}
+	doc.blocks = append(doc.blocks, &block{code: signatures})
+	return doc
+}
+
+// fileName returns the page name of the package, which is its import path from the stores directory on.
+func fileName(pkg *packages.Package) string {
+	path := pkg.ImportPath()
+	if i := strings.Index(path, "/stores/"); i >= 0 {
+		path = path[i+len("/stores/"):]
+	}
+	return strings.ReplaceAll(path, "/", "-")
+}
+
+// entityBlocks returns the heading, table and columns of the entity.
+func entityBlocks(entity *packages.Entity) []*block {
+	kind := "Entity"
+	switch {
+	case entity.IsPrimaryEntity():
+		kind = "Main entity"
+	case entity.IsTranslation() && entity.TranslationParent() != nil:
+		kind = "Translation entity of " + entity.TranslationParent().Name()
+	}
+	blocks := []*block{
+		{heading: "Entity " + entity.Name()},
+		{paragraph: kind + ", stored in table " + entity.TableName() + " with alias " + entity.TableAlias() + "."},
+	}
+	columns := &table{
+		header:      []string{"Column", "Property", "Go type", "SQL type", "Nullable", "Description"},
+		codeColumns: map[int]bool{0: true, 1: true, 2: true, 3: true},
+	}
+	for _, property := range entity.DatabaseProperties() {
+		nullable := "no"
+		if property.IsPointer() {
+			nullable = "yes"
+		}
+		columns.rows = append(columns.rows, []string{
+			property.ColumnName(), property.Name(), property.Type(), property.SQLType(), nullable,
+			property.Description(),
+		})
+	}
+	blocks = append(blocks, &block{table: columns})
+
+	if len(entity.Relations()) > 0 {
+		relations := &table{header: []string{"Property", "Relation", "Entity"}, codeColumns: map[int]bool{0: true}}
+		for _, relation := range entity.Relations() {
+			relations.rows = append(relations.rows, []string{
+				relation.Property().Name(), string(relation.Kind()), relation.Target(),
+			})
+		}
+		blocks = append(blocks, &block{table: relations})
+	}
+	return blocks
+}
+
+func renderMarkdown(doc *document) string {
+	output := &strings.Builder{}
+	output.WriteString("<!-- Code generated by espal-store-synthesizer. DO NOT EDIT. -->\n\n")
+	output.WriteString("# " + doc.title + "\n")
+	for _, block := range doc.blocks {
+		output.WriteString("\n")
+		switch {
+		case block.heading != "":
+			output.WriteString("## " + block.heading + "\n")
+		case block.paragraph != "":
+			output.WriteString(block.paragraph + "\n")
+		case block.table != nil:
+			writeMarkdownRow(output, block.table.header, nil)
+			separators := make([]string, len(block.table.header))
+			for i := range separators {
+				separators[i] = "---"
+			}
+			writeMarkdownRow(output, separators, nil)
+			for _, row := range block.table.rows {
+				writeMarkdownRow(output, row, block.table.codeColumns)
+			}
+		case block.links != nil:
+			for _, link := range block.links {
+				output.WriteString("- [" + link.name + "](" + link.target + ")\n")
+			}
+		default:
+			output.WriteString("```go\n")
+			output.WriteString(strings.Join(block.code, "\n") + "\n")
+			output.WriteString("```\n")
+		}
+	}
+	return output.String()
+}
+
+func writeMarkdownRow(output *strings.Builder, cells []string, codeColumns map[int]bool) {
+	output.WriteString("|")
+	for i, cell := range cells {
+		cell = strings.ReplaceAll(strings.ReplaceAll(cell, "|", "\\|"), "\n", " ")
+		if codeColumns[i] && cell != "" {
+			cell = "`" + cell + "`"
+		}
+		output.WriteString(" " + cell + " |")
+	}
+	output.WriteString("\n")
+}
+
+func renderHTML(doc *document) string {
+	output := &strings.Builder{}
+	output.WriteString("<!DOCTYPE html>\n")
+	output.WriteString("<!-- Code generated by espal-store-synthesizer. DO NOT EDIT. -->\n")
+	output.WriteString("<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	output.WriteString("<title>" + html.EscapeString(doc.title) + "</title>\n")
+	output.WriteString("</head>\n<body>\n")
+	output.WriteString("<h1>" + html.EscapeString(doc.title) + "</h1>\n")
+	for _, block := range doc.blocks {
+		switch {
+		case block.heading != "":
+			output.WriteString("<h2>" + html.EscapeString(block.heading) + "</h2>\n")
+		case block.paragraph != "":
+			output.WriteString("<p>" + html.EscapeString(block.paragraph) + "</p>\n")
+		case block.table != nil:
+			output.WriteString("<table>\n<tr>")
+			for _, cell := range block.table.header {
+				output.WriteString("<th>" + html.EscapeString(cell) + "</th>")
+			}
+			output.WriteString("</tr>\n")
+			for _, row := range block.table.rows {
+				output.WriteString("<tr>")
+				for i, cell := range row {
+					cell = html.EscapeString(cell)
+					if block.table.codeColumns[i] && cell != "" {
+						cell = "<code>" + cell + "</code>"
+					}
+					output.WriteString("<td>" + cell + "</td>")
+				}
+				output.WriteString("</tr>\n")
+			}
+			output.WriteString("</table>\n")
+		case block.links != nil:
+			output.WriteString("<ul>\n")
+			for _, link := range block.links {
+				output.WriteString("<li><a href=\"" + html.EscapeString(link.target) + "\">" +
+					html.EscapeString(link.name) + "</a></li>\n")
+			}
+			output.WriteString("</ul>\n")
+		default:
+			output.WriteString("<pre><code>" + html.EscapeString(strings.Join(block.code, "\n")) + "</code></pre>\n")
+		}
+	}
+	output.WriteString("</body>\n</html>\n")
+	return output.String()
+}
+
+// New returns a new instance of Docs that writes the pages to the directory in the format.
+func New(path string, format string) (*Docs, error) {
+	if path == "" {
+		return nil, errors.New("the documentation needs a directory")
+	}
+	if format != FormatMarkdown && format != FormatHTML {
+		return nil, errors.Errorf("unknown documentation format `%s`", format)
+	}
+	return &Docs{
+		path:   path,
+		format: format,
+	}, nil
+}
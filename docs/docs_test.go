@@ -0,0 +1,95 @@
+package docs
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/espal-digital-development/espal-store-synthesizer/packages"
+	"github.com/juju/errors"
+)
+
+func TestBuild(t *testing.T) {
+	pkg := packages.New(&packages.Config{NamingStrategy: packages.NamingAsIs})
+	if err := pkg.BuildMetaData("../testdata/stores/user"); err != nil {
+		t.Fatal(errors.ErrorStack(err))
+	}
+	tests := []struct {
+		format   string
+		file     string
+		expected []string
+	}{
+		{FormatMarkdown, "index.md", []string{
+			"# Stores\n",
+			"- [github.com/espal-digital-development/espal-core/stores/user](user.md)\n",
+		}},
+		{FormatMarkdown, "user.md", []string{
+			"# Package user\n",
+			"## Entity User\n\nMain entity, stored in table User with alias ue.\n",
+			"| `email` | `email` | `string` | `TEXT` | no | Primary e-mail address. |\n",
+			"| `firstName` | `firstName` | `*string` | `TEXT` | yes |  |\n",
+			"| `addresses` | has-many | Address |\n",
+			"## Entity UserTranslation\n\nTranslation entity of User, stored in table UserTranslation with alias ute.\n",
+			"## Store UsersStore\n",
+			"| `selecterDatabase` | `database.Database` |\n",
+			"```go\nQuery() *UserQuery\n",
+		}},
+		{FormatHTML, "index.html", []string{
+			"<title>Stores</title>",
+			"<li><a href=\"user.html\">github.com/espal-digital-development/espal-core/stores/user</a></li>",
+		}},
+		{FormatHTML, "user.html", []string{
+			"<h2>Entity User</h2>\n<p>Main entity, stored in table User with alias ue.</p>\n<table>\n",
+			"<tr><td><code>email</code></td><td><code>email</code></td><td><code>string</code></td>" +
+				"<td><code>TEXT</code></td><td>no</td><td>Primary e-mail address.</td></tr>",
+			"<tr><td><code>addresses</code></td><td>has-many</td><td>Address</td></tr>",
+			"<pre><code>Query() *UserQuery\n",
+		}},
+	}
+	directories := map[string]string{}
+	for _, test := range tests {
+		directory, ok := directories[test.format]
+		if !ok {
+			directory = t.TempDir()
+			directories[test.format] = directory
+			docs, err := New(directory, test.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := docs.Build([]*packages.Package{pkg}); err != nil {
+				t.Fatal(errors.ErrorStack(err))
+			}
+		}
+		output, err := ioutil.ReadFile(directory + "/" + test.file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, expected := range test.expected {
+			if !strings.Contains(string(output), expected) {
+				t.Errorf("Expected %s in %s\n%s", expected, test.file, output)
+			}
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		path   string
+		format string
+		fails  bool
+	}{
+		{"docs", FormatMarkdown, false},
+		{"docs", FormatHTML, false},
+		{"", FormatMarkdown, true},
+		{"docs", "pdf", true},
+		{"docs", "", true},
+	}
+	for _, test := range tests {
+		if _, err := New(test.path, test.format); (err != nil) != test.fails {
+			t.Errorf("New(%q, %q) should fail: %t, got %v", test.path, test.format, test.fails, err)
+		}
+	}
+	if _, err := New("docs", "pdf"); err == nil || err.Error() != "unknown documentation format `pdf`" {
+		t.Errorf("Expected the unknown format error, got %v", err)
+	}
+}
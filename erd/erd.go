@@ -0,0 +1,260 @@
+package erd
+
+import (
+	"html"
+	"io/ioutil"
+	"regexp"
+	"strings"
+
+	"github.com/espal-digital-development/espal-store-synthesizer/packages"
+	"github.com/espal-digital-development/system/permissions"
+	"github.com/juju/errors"
+)
+
+// creatorTarget is the entity the creator foreign keys refer to, as the creators are joined from its table.
+const creatorTarget = "User"
+
+// ERD package object.
+type ERD struct {
+	mermaidPath string
+	dotPath     string
+	reNonWord   *regexp.Regexp
+	entities    []*packages.Entity
+	edges       []*edge
+}
+
+// edge is a relation between the table of the child, which holds the foreign key, and its parent's table.
+type edge struct {
+	child       *packages.Entity
+	foreignKey  *packages.Property
+	parent      *packages.Entity
+	label       string
+	translation bool
+	creator     bool
+}
+
+// Build writes the diagrams of all the stores' tables and the relations between them.
+func (e *ERD) Build(pkgs []*packages.Package) error {
+	e.entities = []*packages.Entity{}
+	for _, pkg := range pkgs {
+		e.entities = append(e.entities, pkg.AllEntities()...)
+	}
+	e.edges = e.collectEdges()
+	if e.mermaidPath != "" {
+		if err := ioutil.WriteFile(e.mermaidPath, []byte(e.mermaid()), permissions.UserReadWrite); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if e.dotPath != "" {
+		if err := ioutil.WriteFile(e.dotPath, []byte(e.dot()), permissions.UserReadWrite); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// collectEdges returns the relations from the translation entities, the has-many and belongs-to
+// annotations, and the other `...ID` properties that are named after an entity. Every foreign key
+// gets one edge, taken from the first of those that has it. Relations to entities outside of the
+// stores are left out.
+// nolint:gocyclo
+func (e *ERD) collectEdges() []*edge {
+	edges := []*edge{}
+	seen := map[*packages.Property]bool{}
+	add := func(child *packages.Entity, foreignKey *packages.Property, parent *packages.Entity, label string,
+		translation bool, creator bool) {
+		if foreignKey == nil || parent == nil || seen[foreignKey] || !foreignKey.IsDatabaseField() {
+			return
+		}
+		seen[foreignKey] = true
+		edges = append(edges, &edge{
+			child:       child,
+			foreignKey:  foreignKey,
+			parent:      parent,
+			label:       label,
+			translation: translation,
+			creator:     creator,
+		})
+	}
+
+	for _, entity := range e.entities {
+		if entity.IsTranslation() {
+			add(entity, entity.TranslationForeignKey(), entity.TranslationParent(), "translation", true, false)
+		}
+	}
+	for _, entity := range e.entities {
+		for _, relation := range entity.Relations() {
+			if relation.Kind() == packages.RelationHasMany && relation.TargetEntity() != nil {
+				child := relation.TargetEntity()
+				add(child, child.Property(relation.ForeignKey()), entity, relation.Property().Name(), false, false)
+			}
+		}
+	}
+	for _, entity := range e.entities {
+		for _, relation := range entity.Relations() {
+			if relation.Kind() == packages.RelationBelongsTo {
+				add(entity, relation.Property(), e.resolve(entity, relation.Target()), relation.Property().Name(),
+					false, false)
+			}
+		}
+	}
+	for _, entity := range e.entities {
+		for _, property := range entity.CreatorForeignKeys() {
+			add(entity, property, e.resolve(entity, creatorTarget), property.Name(), false, true)
+		}
+		for _, property := range entity.DatabaseProperties() {
+			name := strings.TrimPrefix(property.Name(), "_")
+			if len(name) <= len("ID") || !strings.HasSuffix(name, "ID") {
+				continue
+			}
+			target := strings.Title(strings.TrimSuffix(name, "ID"))
+			add(entity, property, e.resolve(entity, target), property.Name(), false, false)
+		}
+	}
+	return edges
+}
+
+// resolve returns the entity with the name, preferring the one in the same package as from.
+func (e *ERD) resolve(from *packages.Entity, name string) *packages.Entity {
+	var found *packages.Entity
+	for _, entity := range e.entities {
+		if entity.Name() != name {
+			continue
+		}
+		if entity.PackageName() == from.PackageName() {
+			return entity
+		}
+		if found == nil {
+			found = entity
+		}
+	}
+	return found
+}
+
+// isForeignKey returns if the property is the foreign key of an edge.
+func (e *ERD) isForeignKey(property *packages.Property) bool {
+	for _, edge := range e.edges {
+		if edge.foreignKey == property {
+			return true
+		}
+	}
+	return false
+}
+
+// word replaces the characters Mermaid doesn't allow in names and types.
+func (e *ERD) word(value string) string {
+	return e.reNonWord.ReplaceAllString(value, "_")
+}
+
+// mermaid returns the Mermaid erDiagram of the tables.
+func (e *ERD) mermaid() string {
+	output := &strings.Builder{}
+	output.WriteString("%% Code generated by espal-store-synthesizer. DO NOT EDIT.\n")
+	output.WriteString("erDiagram\n")
+	for _, entity := range e.entities {
+		output.WriteString("    " + e.word(entity.TableName()) + " {\n")
+		for _, property := range entity.DatabaseProperties() {
+			output.WriteString("        " + e.word(property.SQLType()) + " " + e.word(property.ColumnName()))
+			keys := []string{}
+			if property.Name() == "id" {
+				keys = append(keys, "PK")
+			}
+			if e.isForeignKey(property) {
+				keys = append(keys, "FK")
+			}
+			if len(keys) > 0 {
+				output.WriteString(" " + strings.Join(keys, ", "))
+			}
+			comments := []string{}
+			if property.IsPointer() {
+				comments = append(comments, "nullable")
+			}
+			if description := property.Description(); description != "" {
+				comments = append(comments, description)
+			}
+			if len(comments) > 0 {
+				output.WriteString(" \"" + strings.ReplaceAll(strings.Join(comments, ", "), `"`, "'") + "\"")
+			}
+			output.WriteString("\n")
+		}
+		output.WriteString("    }\n")
+	}
+	for _, edge := range e.edges {
+		parentSide := "||"
+		if edge.foreignKey.IsPointer() {
+			parentSide = "|o"
+		}
+		output.WriteString("    " + e.word(edge.parent.TableName()) + " " + parentSide + "--o{ " +
+			e.word(edge.child.TableName()) + " : \"" + edge.label + "\"\n")
+	}
+	return output.String()
+}
+
+// dot returns the Graphviz DOT digraph of the tables, with an edge from every foreign key column to the
+// parent's id column. Translations are dashed and creators dotted.
+func (e *ERD) dot() string {
+	output := &strings.Builder{}
+	output.WriteString("// Code generated by espal-store-synthesizer. DO NOT EDIT.\n")
+	output.WriteString("digraph stores {\n")
+	output.WriteString("  rankdir=LR;\n")
+	output.WriteString("  node [shape=plaintext];\n")
+	for _, entity := range e.entities {
+		output.WriteString("  " + quote(entity.TableName()) + " [label=<\n")
+		output.WriteString("    <table border=\"0\" cellborder=\"1\" cellspacing=\"0\">\n")
+		output.WriteString("      <tr><td bgcolor=\"lightgrey\" colspan=\"2\"><b>" +
+			html.EscapeString(entity.TableName()) + "</b> " + html.EscapeString(entity.TableAlias()) + "</td></tr>\n")
+		for _, property := range entity.DatabaseProperties() {
+			column := html.EscapeString(property.ColumnName())
+			switch {
+			case property.Name() == "id":
+				column = "<u>" + column + "</u>"
+			case e.isForeignKey(property):
+				column = "<i>" + column + "</i>"
+			}
+			sqlType := html.EscapeString(property.SQLType())
+			if property.IsPointer() {
+				sqlType += " NULL"
+			}
+			output.WriteString("      <tr><td port=\"" + html.EscapeString(property.ColumnName()) + "\" align=\"left\">" +
+				column + "</td><td align=\"left\">" + sqlType + "</td></tr>\n")
+		}
+		output.WriteString("    </table>\n")
+		output.WriteString("  >];\n")
+	}
+	for _, edge := range e.edges {
+		target := quote(edge.parent.TableName())
+		if id := edge.parent.Property("id"); id != nil {
+			target += ":" + quote(id.ColumnName())
+		}
+		attributes := "label=" + quote(edge.label)
+		switch {
+		case edge.translation:
+			attributes += ", style=dashed"
+		case edge.creator:
+			// Every table has creators, so keep them from drawing the attention
+			attributes += ", style=dotted, color=grey"
+		}
+		output.WriteString("  " + quote(edge.child.TableName()) + ":" + quote(edge.foreignKey.ColumnName()) + " -> " +
+			target + " [" + attributes + "];\n")
+	}
+	output.WriteString("}\n")
+	return output.String()
+}
+
+// quote returns the DOT ID of the value.
+func quote(value string) string {
+	return `"` + strings.ReplaceAll(strings.ReplaceAll(value, `\`, `\\`), `"`, `\"`) + `"`
+}
+
+// New returns a new instance of ERD that writes a Mermaid diagram to mermaidPath and a Graphviz
+// DOT diagram to dotPath. Either path can be empty to skip that diagram.
+func New(mermaidPath string, dotPath string) (*ERD, error) {
+	if mermaidPath == "" && dotPath == "" {
+		return nil, errors.New("the diagrams need a Mermaid or DOT path")
+	}
+	return &ERD{
+		mermaidPath: mermaidPath,
+		dotPath:     dotPath,
+		reNonWord:   regexp.MustCompile(`[^A-Za-z0-9_\-()\[\]]`),
+	}, nil
+}
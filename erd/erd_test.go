@@ -0,0 +1,71 @@
+package erd
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/espal-digital-development/espal-store-synthesizer/packages"
+	"github.com/juju/errors"
+)
+
+func TestBuildEdges(t *testing.T) {
+	pkgs := []*packages.Package{}
+	for _, name := range []string{"user", "order"} {
+		pkg := packages.New(&packages.Config{NamingStrategy: packages.NamingAsIs})
+		if err := pkg.BuildMetaData("../testdata/stores/" + name); err != nil {
+			t.Fatal(errors.ErrorStack(err))
+		}
+		pkgs = append(pkgs, pkg)
+	}
+	directory := t.TempDir()
+	erd, err := New(directory+"/stores.mmd", directory+"/stores.dot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := erd.Build(pkgs); err != nil {
+		t.Fatal(errors.ErrorStack(err))
+	}
+
+	tests := []struct {
+		file     string
+		expected []string
+		missing  []string
+	}{
+		{"stores.mmd", []string{
+			// Has-many, which also covers the child's belongs-to of the same foreign key
+			"User ||--o{ Address : \"addresses\"\n",
+			"order ||--o{ OrderLine : \"lines\"\n",
+			// Belongs-to only
+			"OrderNote |o--o{ OrderNote : \"replyToID\"\n",
+			"User ||--o{ UserTranslation : \"translation\"\n",
+			"User |o--o{ Address : \"updatedByID\"\n",
+		}, []string{
+			"User ||--o{ Address : \"userID\"",
+			"order ||--o{ OrderLine : \"orderID\"",
+		}},
+		{"stores.dot", []string{
+			"\"Address\":\"userID\" -> \"User\":\"id\" [label=\"addresses\"];\n",
+			"\"OrderLine\":\"orderID\" -> \"order\":\"id\" [label=\"lines\"];\n",
+			"\"OrderNote\":\"replyToID\" -> \"OrderNote\":\"id\" [label=\"replyToID\"];\n",
+			"\"UserTranslation\":\"userID\" -> \"User\":\"id\" [label=\"translation\", style=dashed];\n",
+			"\"order\":\"created_by_id\" -> \"User\":\"id\" [label=\"createdByID\", style=dotted, color=grey];\n",
+		}, nil},
+	}
+	for _, test := range tests {
+		output, err := ioutil.ReadFile(directory + "/" + test.file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, expected := range test.expected {
+			if !strings.Contains(string(output), expected) {
+				t.Errorf("Expected %s in %s\n%s", expected, test.file, output)
+			}
+		}
+		for _, missing := range test.missing {
+			if strings.Contains(string(output), missing) {
+				t.Errorf("%s shouldn't contain %s", test.file, missing)
+			}
+		}
+	}
+}
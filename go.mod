@@ -3,8 +3,9 @@ module github.com/espal-digital-development/espal-store-synthesizer
 go 1.16
 
 require (
-	github.com/espal-digital-development/system v0.0.0-20210709095725-fc2ca8344570 // indirect
+	github.com/espal-digital-development/system v0.0.0-20210709095725-fc2ca8344570
 	github.com/juju/errors v0.0.0-20200330140219-3fe23663418f
 	github.com/juju/testing v0.0.0-20210324180055-18c50b0c2098 // indirect
 	github.com/mattn/go-zglob v0.0.3
+	gopkg.in/yaml.v2 v2.4.0
 )
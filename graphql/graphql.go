@@ -0,0 +1,440 @@
+package graphql
+
+import (
+	"go/format"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/espal-digital-development/espal-store-synthesizer/packages"
+	"github.com/espal-digital-development/system/permissions"
+	"github.com/juju/errors"
+)
+
+// scalars are the custom scalars the schema declares for the Go types GraphQL has no type for.
+const scalars = `"""RFC 3339 time."""
+scalar Time
+
+"""Duration in nanoseconds."""
+scalar Duration
+
+"""Base64 encoded byte-array."""
+scalar Bytes
+
+"""64-bit integer."""
+scalar Int64
+`
+
+// GraphQL package object.
+type GraphQL struct {
+	path          string
+	resolversPath string
+	typeNames     map[*packages.Entity]string
+	reName        *regexp.Regexp
+}
+
+// queryField is a Query field resolved by a Store read method.
+type queryField struct {
+	pkg    *packages.Package
+	name   string
+	method *packages.Function
+	// contextMethod is the method's Context variant, if the store has one.
+	contextMethod *packages.Function
+	// hasOK is set for methods that also return if anything was found.
+	hasOK      bool
+	returnType string
+}
+
+// Build writes the schema with a type per entity and a Query field per Store read method, and the
+// resolver stubs if they have a path.
+func (g *GraphQL) Build(pkgs []*packages.Package) error {
+	g.typeNames = packages.TypeNames(pkgs)
+	fields := []*queryField{}
+	for _, pkg := range pkgs {
+		fields = append(fields, g.queryFields(pkg)...)
+	}
+	if err := ioutil.WriteFile(g.path, []byte(g.schema(pkgs, fields)), permissions.UserReadWrite); err != nil {
+		return errors.Trace(err)
+	}
+	if g.resolversPath == "" {
+		return nil
+	}
+	if err := os.MkdirAll(g.resolversPath, permissions.UserReadWriteExecute); err != nil {
+		return errors.Trace(err)
+	}
+	resolvers, err := format.Source([]byte(g.resolvers(pkgs, fields)))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(ioutil.WriteFile(g.resolversPath+"/resolvers_synthesized.go", resolvers,
+		permissions.UserReadWrite))
+}
+
+// queryFields returns the package's Store read methods that can be Query fields. Those are the
+// `Get` methods that return the package's entities or pages of them, with parameters that GraphQL
+// can pass as arguments.
+func (g *GraphQL) queryFields(pkg *packages.Package) []*queryField {
+	methods := pkg.Store().InterfaceMethods()
+	contextMethods := map[string]*packages.Function{}
+	for _, method := range methods {
+		if strings.HasSuffix(method.Name(), "Context") {
+			contextMethods[strings.TrimSuffix(method.Name(), "Context")] = method
+		}
+	}
+	fields := []*queryField{}
+	for _, method := range methods {
+		if !strings.HasPrefix(method.Name(), "Get") || strings.HasSuffix(method.Name(), "Context") {
+			continue
+		}
+		returnValues := method.ReturnValues()
+		if len(returnValues) < 2 || len(returnValues) > 3 || returnValues[len(returnValues)-1].Type() != "error" {
+			continue
+		}
+		field := &queryField{
+			pkg:           pkg,
+			name:          g.fieldName(pkg, method),
+			method:        method,
+			contextMethod: contextMethods[method.Name()],
+			hasOK:         len(returnValues) == 3 && returnValues[1].Type() == "bool",
+			returnType:    g.returnType(pkg, returnValues[0].Type()),
+		}
+		if field.returnType == "" || (len(returnValues) == 3 && !field.hasOK) {
+			continue
+		}
+		supported := true
+		for _, parameter := range method.Parameters() {
+			if g.argumentType(pkg, parameter) == "" {
+				supported = false
+			}
+		}
+		if supported {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// fieldName returns the Query field name of the read method, which is the main entity's name followed
+// by the method's name without `Get`. GetOne is named after just the entity.
+func (g *GraphQL) fieldName(pkg *packages.Package, method *packages.Function) string {
+	name := g.typeNames[pkg.MainEntity()]
+	name = strings.ToLower(name[:1]) + name[1:]
+	if method.Name() == "GetOne" {
+		return name
+	}
+	return name + strings.TrimPrefix(method.Name(), "Get")
+}
+
+// returnType returns the GraphQL type of the Go return type if it's one of the package's entities,
+// a slice of them or a page of the main entity.
+func (g *GraphQL) returnType(pkg *packages.Package, goType string) string {
+	if pkg.MainEntity().CursorProperty() != nil && goType == "*"+pkg.MainEntity().Name()+"Page" {
+		return g.typeNames[pkg.MainEntity()] + "Page!"
+	}
+	list := strings.HasPrefix(goType, "[]")
+	name := strings.TrimPrefix(strings.TrimPrefix(goType, "[]"), "*")
+	for _, entity := range pkg.AllEntities() {
+		if entity.Name() != name {
+			continue
+		}
+		if list {
+			return "[" + g.typeNames[entity] + "!]"
+		}
+		return g.typeNames[entity]
+	}
+	return ""
+}
+
+// argumentType returns the GraphQL type of the parameter or an empty string if it has none.
+func (g *GraphQL) argumentType(pkg *packages.Package, parameter *packages.FunctionParameter) string {
+	if pkg.MainEntity().CursorProperty() != nil && parameter.Type() == pkg.MainEntity().Name()+"PageOptions" {
+		return g.typeNames[pkg.MainEntity()] + "PageOptions"
+	}
+	goType := parameter.Type()
+	list := strings.HasPrefix(goType, "...") || strings.HasPrefix(goType, "[]")
+	goType = strings.TrimPrefix(strings.TrimPrefix(goType, "..."), "[]")
+	name := scalarType(goType)
+	if name == "" {
+		return ""
+	}
+	if name == "String" && (parameter.Name() == "id" || parameter.Name() == "ids") {
+		name = "ID"
+	}
+	if list {
+		return "[" + name + "!]!"
+	}
+	return name + "!"
+}
+
+// scalarType returns the GraphQL type of the Go type or an empty string if it has none.
+func scalarType(goType string) string {
+	switch goType {
+	case "string":
+		return "String"
+	case "bool":
+		return "Boolean"
+	case "int8", "int16", "int32", "uint8", "uint16":
+		return "Int"
+	case "int", "int64", "uint", "uint32", "uint64":
+		return "Int64"
+	case "float32", "float64":
+		return "Float"
+	case "time.Time":
+		return "Time"
+	case "time.Duration":
+		return "Duration"
+	case "[]byte":
+		return "Bytes"
+	}
+	return ""
+}
+
+// schema returns the SDL of the entity types, the page types and the Query.
+// nolint:funlen
+func (g *GraphQL) schema(pkgs []*packages.Package, fields []*queryField) string {
+	output := &strings.Builder{}
+	output.WriteString("# Code generated by espal-store-synthesizer. DO NOT EDIT.\n\n")
+	output.WriteString(scalars)
+	for _, pkg := range pkgs {
+		for _, entity := range pkg.AllEntities() {
+			g.writeType(output, entity)
+		}
+		main := pkg.MainEntity()
+		if main.CursorProperty() == nil {
+			continue
+		}
+		name := g.typeNames[main]
+		output.WriteString("\n")
+		output.WriteString("\"\"\"Page of " + name + " entities. Total is only counted for offset pagination and the " +
+			"cursors are only set for keyset pagination.\"\"\"\n")
+		output.WriteString("type " + name + "Page {\n")
+		output.WriteString("  items: [" + name + "!]\n")
+		output.WriteString("  total: Int64!\n")
+		output.WriteString("  hasNext: Boolean!\n")
+		output.WriteString("  hasPrev: Boolean!\n")
+		output.WriteString("  nextCursor: String!\n")
+		output.WriteString("  prevCursor: String!\n")
+		output.WriteString("}\n")
+		output.WriteString("\n")
+		output.WriteString("\"\"\"Offset pagination with page or keyset pagination with after or before.\"\"\"\n")
+		output.WriteString("input " + name + "PageOptions {\n")
+		output.WriteString("  limit: Int64\n")
+		output.WriteString("  page: Int64\n")
+		output.WriteString("  after: String\n")
+		output.WriteString("  before: String\n")
+		output.WriteString("  desc: Boolean\n")
+		output.WriteString("  withCreators: Boolean\n")
+		if main.SoftDeleteProperty() != nil {
+			output.WriteString("  includeDeleted: Boolean\n")
+		}
+		output.WriteString("}\n")
+	}
+
+	if len(fields) == 0 {
+		return output.String()
+	}
+	output.WriteString("\n")
+	output.WriteString("type Query {\n")
+	for _, field := range fields {
+		output.WriteString("  " + field.name)
+		if len(field.method.Parameters()) > 0 {
+			arguments := []string{}
+			for _, parameter := range field.method.Parameters() {
+				arguments = append(arguments, parameter.Name()+": "+g.argumentType(field.pkg, parameter))
+			}
+			output.WriteString("(" + strings.Join(arguments, ", ") + ")")
+		}
+		output.WriteString(": " + field.returnType + "\n")
+	}
+	output.WriteString("}\n")
+	return output.String()
+}
+
+// writeType writes the object type of the entity. The fields follow the entity's JSON, apart from
+// names GraphQL doesn't allow.
+func (g *GraphQL) writeType(output *strings.Builder, entity *packages.Entity) {
+	output.WriteString("\n")
+	output.WriteString("\"\"\"" + entity.Name() + " of the " + entity.PackageName() + " store.\"\"\"\n")
+	output.WriteString("type " + g.typeNames[entity] + " {\n")
+	for _, property := range entity.JSONProperties() {
+		name := property.JSONName()
+		fieldType := g.fieldType(entity, property)
+		if !g.reName.MatchString(name) || fieldType == "" {
+			continue
+		}
+		if description := property.Description(); description != "" {
+			output.WriteString("  \"\"\"" + strings.ReplaceAll(description, `"""`, `\"""`) + "\"\"\"\n")
+		}
+		output.WriteString("  " + name + ": " + fieldType + "\n")
+	}
+	output.WriteString("}\n")
+}
+
+// fieldType returns the GraphQL type of the property or an empty string if it has none.
+func (g *GraphQL) fieldType(entity *packages.Entity, property *packages.Property) string {
+	for _, relation := range entity.Relations() {
+		if relation.Property() == property && relation.TargetEntity() != nil &&
+			relation.Kind() == packages.RelationHasMany {
+			return "[" + g.typeNames[relation.TargetEntity()] + "!]"
+		}
+	}
+	if property.Name() == "id" {
+		return "ID!"
+	}
+	name := scalarType(property.BaseType())
+	if name == "" && strings.HasPrefix(property.BaseType(), "[]") {
+		name = scalarType(strings.TrimPrefix(strings.TrimPrefix(property.BaseType(), "[]"), "*"))
+		if name != "" {
+			return "[" + name + "!]"
+		}
+	}
+	switch {
+	case name == "":
+		return ""
+	case property.IsPointer() || property.IsBytes():
+		return name
+	}
+	return name + "!"
+}
+
+// resolvers returns the Go source of the resolver stubs, which resolve the Query fields with the
+// Store interfaces.
+// nolint:funlen
+func (g *GraphQL) resolvers(pkgs []*packages.Package, fields []*queryField) string {
+	aliases := importAliases(pkgs)
+	body := &strings.Builder{}
+	body.WriteString("\n")
+	body.WriteString("// Resolver resolves the Query fields with the stores.\n")
+	body.WriteString("type Resolver struct {\n")
+	for _, pkg := range pkgs {
+		body.WriteString("\t" + storeFieldName(pkg, aliases) + " " + aliases[pkg] + ".Store\n")
+	}
+	body.WriteString("}\n")
+
+	for _, field := range fields {
+		alias := aliases[field.pkg]
+		parameters := []string{"ctx context.Context"}
+		arguments := []string{}
+		for _, parameter := range field.method.Parameters() {
+			goType := parameter.Type()
+			argument := parameter.Name()
+			switch {
+			case strings.HasPrefix(goType, "..."):
+				goType = "[]" + strings.TrimPrefix(goType, "...")
+				argument += "..."
+			case strings.HasSuffix(goType, "PageOptions"):
+				goType = "*" + alias + "." + goType
+				argument = "options"
+			}
+			parameters = append(parameters, parameter.Name()+" "+goType)
+			arguments = append(arguments, argument)
+		}
+		returnType := field.method.ReturnValues()[0].Type()
+		returnType = strings.Replace(returnType, "*", "*"+alias+".", 1)
+		if field.contextMethod != nil {
+			arguments = append([]string{"ctx"}, arguments...)
+		}
+		call := "r." + storeFieldName(field.pkg, aliases) + "." + field.method.Name()
+		if field.contextMethod != nil {
+			call = "r." + storeFieldName(field.pkg, aliases) + "." + field.contextMethod.Name()
+		}
+		call += "(" + strings.Join(arguments, ", ") + ")"
+
+		methodName := strings.ToUpper(field.name[:1]) + field.name[1:]
+		body.WriteString("\n")
+		body.WriteString("// " + methodName + " resolves the `" + field.name + "` Query field with " +
+			field.method.Name() + ".\n")
+		body.WriteString("func (r *Resolver) " + methodName + "(" + strings.Join(parameters, ", ") + ") (" +
+			returnType + ", error) {\n")
+		for _, parameter := range field.method.Parameters() {
+			if strings.HasSuffix(parameter.Type(), "PageOptions") {
+				body.WriteString("\toptions := " + alias + "." + parameter.Type() + "{}\n")
+				body.WriteString("\tif " + parameter.Name() + " != nil {\n")
+				body.WriteString("\t\toptions = *" + parameter.Name() + "\n")
+				body.WriteString("\t}\n")
+			}
+		}
+		if field.hasOK {
+			body.WriteString("\tresult, ok, err := " + call + "\n")
+			body.WriteString("\tif err != nil || !ok {\n")
+			body.WriteString("\t\treturn nil, errors.Trace(err)\n")
+			body.WriteString("\t}\n")
+		} else {
+			body.WriteString("\tresult, err := " + call + "\n")
+			body.WriteString("\tif err != nil {\n")
+			body.WriteString("\t\treturn nil, errors.Trace(err)\n")
+			body.WriteString("\t}\n")
+		}
+		body.WriteString("\treturn result, nil\n")
+		body.WriteString("}\n")
+	}
+
+	output := &strings.Builder{}
+	output.WriteString("// Code generated by espal-store-synthesizer. DO NOT EDIT.\n")
+	output.WriteString("package " + filepath.Base(g.resolversPath) + "\n\n")
+	output.WriteString("import (\n")
+	if len(fields) > 0 {
+		output.WriteString("\t\"context\"\n")
+	}
+	if strings.Contains(body.String(), " time.") {
+		output.WriteString("\t\"time\"\n")
+	}
+	imports := make([]string, 0, len(pkgs))
+	for _, pkg := range pkgs {
+		if aliases[pkg] == pkg.Name() {
+			imports = append(imports, "\t\""+pkg.ImportPath()+"\"\n")
+		} else {
+			imports = append(imports, "\t"+aliases[pkg]+" \""+pkg.ImportPath()+"\"\n")
+		}
+	}
+	sort.Strings(imports)
+	output.WriteString("\n")
+	for _, imp := range imports {
+		output.WriteString(imp)
+	}
+	if len(fields) > 0 {
+		output.WriteString("\t\"github.com/juju/errors\"\n")
+	}
+	output.WriteString(")\n")
+	output.WriteString(body.String())
+	return output.String()
+}
+
+// importAliases returns the import names of the store packages, which are the package names unless
+// several packages share a name.
+func importAliases(pkgs []*packages.Package) map[*packages.Package]string {
+	counts := map[string]int{}
+	for _, pkg := range pkgs {
+		counts[pkg.Name()]++
+	}
+	aliases := map[*packages.Package]string{}
+	for _, pkg := range pkgs {
+		aliases[pkg] = pkg.Name()
+		if counts[pkg.Name()] > 1 {
+			parent := filepath.Base(filepath.Dir(pkg.ImportPath()))
+			aliases[pkg] = parent + pkg.Name()
+		}
+	}
+	return aliases
+}
+
+// storeFieldName returns the name of the Resolver's field for the package's Store.
+func storeFieldName(pkg *packages.Package, aliases map[*packages.Package]string) string {
+	return strings.Title(aliases[pkg]) + "Store"
+}
+
+// New returns a new instance of GraphQL that writes the schema to the path. With a resolversPath it
+// also writes resolver stubs into that directory, of which the last element is the package name.
+func New(path string, resolversPath string) (*GraphQL, error) {
+	if path == "" {
+		return nil, errors.New("the GraphQL schema needs a path")
+	}
+	return &GraphQL{
+		path:          path,
+		resolversPath: strings.TrimRight(resolversPath, "/"),
+		reName:        regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`),
+	}, nil
+}
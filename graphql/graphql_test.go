@@ -0,0 +1,62 @@
+package graphql
+
+import (
+	"flag"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/espal-digital-development/espal-store-synthesizer/packages"
+	"github.com/espal-digital-development/system/permissions"
+	"github.com/juju/errors"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func TestBuild(t *testing.T) {
+	pkg := packages.New(&packages.Config{NamingStrategy: packages.NamingAsIs})
+	if err := pkg.BuildMetaData("../testdata/stores/user"); err != nil {
+		t.Fatal(errors.ErrorStack(err))
+	}
+	directory := t.TempDir()
+	graphQL, err := New(directory+"/schema.graphql", directory+"/resolvers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := graphQL.Build([]*packages.Package{pkg}); err != nil {
+		t.Fatal(errors.ErrorStack(err))
+	}
+
+	schema, err := ioutil.ReadFile(graphQL.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	goldenPath := "testdata/user.graphql"
+	if *update {
+		if err := ioutil.WriteFile(goldenPath, schema, permissions.UserReadWrite); err != nil {
+			t.Fatal(err)
+		}
+	}
+	golden, err := ioutil.ReadFile(goldenPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(schema) != string(golden) {
+		t.Errorf("The schema doesn't match %s, got\n%s", goldenPath, schema)
+	}
+
+	resolvers, err := ioutil.ReadFile(directory + "/resolvers/resolvers_synthesized.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"package resolvers\n",
+		"type Resolver struct {\n\tUserStore user.Store\n}",
+		"func (r *Resolver) UserPage(ctx context.Context, opts *user.UserPageOptions) (*user.UserPage, error) {",
+		"\tresult, ok, err := r.UserStore.GetTranslation(id, language, field)\n",
+	} {
+		if !strings.Contains(string(resolvers), expected) {
+			t.Errorf("Expected %s in the resolvers\n%s", expected, resolvers)
+		}
+	}
+}
@@ -1,53 +1,233 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 
+	"github.com/espal-digital-development/espal-store-synthesizer/docs"
+	"github.com/espal-digital-development/espal-store-synthesizer/erd"
+	"github.com/espal-digital-development/espal-store-synthesizer/graphql"
 	"github.com/espal-digital-development/espal-store-synthesizer/meta"
+	"github.com/espal-digital-development/espal-store-synthesizer/openapi"
 	"github.com/espal-digital-development/espal-store-synthesizer/packages"
+	"github.com/espal-digital-development/espal-store-synthesizer/protobuf"
+	"github.com/espal-digital-development/espal-store-synthesizer/seed"
+	"github.com/espal-digital-development/espal-store-synthesizer/typescript"
 	"github.com/espal-digital-development/system/permissions"
 	"github.com/juju/errors"
 	"github.com/mattn/go-zglob"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "seed" {
+		if err := seedCommand(os.Args[2:]); err != nil {
+			log.Fatal(errors.ErrorStack(err))
+		}
+		return
+	}
+	if err := synthesizeCommand(os.Args[1:]); err != nil {
+		log.Fatal(errors.ErrorStack(err))
+	}
+}
+
+// configFlags registers the flags that configure how the packages are read.
+func configFlags(flags *flag.FlagSet) func() (*packages.Config, error) {
+	namingStrategyName := flags.String("naming", string(packages.NamingAsIs),
+		"naming strategy for table and column names (as-is, snake or plural-snake)")
+	maxParams := flags.Int("max-params", packages.DefaultMaxParams,
+		"maximum bind parameters per statement, used to chunk multi-row inserts and updates")
+	return func() (*packages.Config, error) {
+		namingStrategy, err := packages.ParseNamingStrategy(*namingStrategyName)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return &packages.Config{
+			NamingStrategy: namingStrategy,
+			MaxParams:      *maxParams,
+		}, nil
+	}
+}
+
+func getStoresPath() (string, error) {
 	storesPath, err := os.Getwd()
 	if err != nil {
-		log.Fatal(errors.ErrorStack(err))
+		return "", errors.Trace(err)
 	}
 	if !strings.HasSuffix(storesPath, "/stores") {
 		storesPath += "/stores"
 	}
+	return storesPath, nil
+}
 
-	packages, err := collectPackages(storesPath)
+func synthesizeCommand(args []string) error {
+	flags := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	config := configFlags(flags)
+	openAPIPath := flags.String("openapi", "",
+		"YAML file (.yaml or .yml) to write an OpenAPI 3 document with a schema per entity to")
+	openAPIInternal := flags.Bool("openapi-internal", false,
+		"include the @synthesize-no-db-field properties in the OpenAPI schemas")
+	typeScriptPath := flags.String("typescript", "", "file to write TypeScript definitions of the entities to")
+	graphQLPath := flags.String("graphql", "", "file to write a GraphQL schema with the entities and store reads to")
+	graphQLResolversPath := flags.String("graphql-resolvers", "",
+		"directory to write GraphQL resolver stubs that call the stores to")
+	docsPath := flags.String("docs", "", "directory to write a data dictionary page per package to")
+	docsFormat := flags.String("docs-format", docs.FormatMarkdown, "format of the data dictionary (markdown or html)")
+	mermaidPath := flags.String("mermaid", "", "file to write a Mermaid ER diagram of the stores to")
+	dotPath := flags.String("dot", "", "file to write a Graphviz DOT ER diagram of the stores to")
+	protoPath := flags.String("proto", "",
+		"directory to write the .proto files to, with converters built with the `"+protobuf.BuildTag+"` tag")
+	if err := flags.Parse(args); err != nil {
+		return errors.Trace(err)
+	}
+	packagesConfig, err := config()
 	if err != nil {
-		log.Fatal(errors.ErrorStack(err))
+		return errors.Trace(err)
+	}
+
+	storesPath, err := getStoresPath()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	packages, err := collectPackages(storesPath, packagesConfig)
+	if err != nil {
+		return errors.Trace(err)
 	}
 	for _, pkg := range packages {
+		if err := pkg.RemoveSynthesizedFiles(); err != nil {
+			return errors.Trace(err)
+		}
 		if err := buildOutputForPackage(pkg); err != nil {
-			log.Fatal(errors.ErrorStack(err))
+			return errors.Trace(err)
 		}
 	}
 	meta, err := meta.New()
 	if err != nil {
-		log.Fatal(errors.ErrorStack(err))
+		return errors.Trace(err)
 	}
 	if err := meta.Build(packages); err != nil {
-		log.Fatal(errors.ErrorStack(err))
+		return errors.Trace(err)
+	}
+	if *openAPIPath != "" {
+		openAPI, err := openapi.New(*openAPIPath, *openAPIInternal)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := openAPI.Build(packages); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if *typeScriptPath != "" {
+		typeScript, err := typescript.New(*typeScriptPath)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := typeScript.Build(packages); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if *graphQLResolversPath != "" && *graphQLPath == "" {
+		return errors.New("-graphql-resolvers needs -graphql")
+	}
+	if *graphQLPath != "" {
+		graphQL, err := graphql.New(*graphQLPath, *graphQLResolversPath)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := graphQL.Build(packages); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if *docsPath != "" {
+		documentation, err := docs.New(*docsPath, *docsFormat)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := documentation.Build(packages); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if *mermaidPath != "" || *dotPath != "" {
+		diagrams, err := erd.New(*mermaidPath, *dotPath)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := diagrams.Build(packages); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if *protoPath != "" {
+		proto, err := protobuf.New(*protoPath, filepath.Dir(storesPath))
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := proto.Build(packages); err != nil {
+			return errors.Trace(err)
+		}
 	}
 
 	if out, err := exec.Command("go", "fmt", storesPath+"/...").Output(); err != nil {
 		fmt.Println(string(out))
-		log.Fatal(errors.ErrorStack(err))
+		return errors.Trace(err)
 	}
+	return nil
 }
 
-func collectPackages(path string) ([]*packages.Package, error) {
+func seedCommand(args []string) error {
+	flags := flag.NewFlagSet(os.Args[0]+" seed", flag.ExitOnError)
+	config := configFlags(flags)
+	format := flags.String("format", seed.FormatSQL, "output format (sql or go)")
+	packageName := flags.String("package", "seeds", "package name of the Go output")
+	outputPath := flags.String("out", "", "file to write the output to instead of stdout")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s seed [flags] <seed file>\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return errors.Trace(err)
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return errors.New("expected one seed file")
+	}
+	packagesConfig, err := config()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	data, err := ioutil.ReadFile(flags.Arg(0))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	storesPath, err := getStoresPath()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	packages, err := collectPackages(storesPath, packagesConfig)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	seeder, err := seed.New(packages)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	output, err := seeder.Build(data, *format, *packageName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if *outputPath == "" {
+		_, err := os.Stdout.Write(output)
+		return errors.Trace(err)
+	}
+	return errors.Trace(ioutil.WriteFile(*outputPath, output, permissions.UserReadWrite))
+}
+
+func collectPackages(path string, config *packages.Config) ([]*packages.Package, error) {
 	pkgs := []*packages.Package{}
 	entries, err := zglob.Glob(path + "/**/*")
 	if err != nil {
@@ -68,11 +248,11 @@ func collectPackages(path string) ([]*packages.Package, error) {
 		}
 
 		// TODO :: This works, but can use some more strictness
-		if strings.HasSuffix(entry, "mock") {
+		if strings.HasSuffix(entry, "mock") || strings.HasSuffix(entry, "/"+packages.FixturesPackageName) {
 			continue
 		}
 
-		pkg := packages.New()
+		pkg := packages.New(config)
 		if err := pkg.BuildMetaData(entry); err != nil {
 			log.Fatal(errors.ErrorStack(err))
 		}
@@ -132,6 +312,35 @@ func buildOutputForPackage(pkg *packages.Package) error {
 		return errors.Trace(err)
 	}
 
+	queryData, err := pkg.Store().BuildQueryFileOutput()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := ioutil.WriteFile(pkg.Path()+"/query_synthesized.go", queryData,
+		permissions.UserReadWrite); err != nil {
+		return errors.Trace(err)
+	}
+
+	if err := os.MkdirAll(pkg.FixturePath(), permissions.UserReadWriteExecute); err != nil {
+		return errors.Trace(err)
+	}
+	fixtureData, err := pkg.BuildFixtureFileOutput()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := ioutil.WriteFile(pkg.FixturePath()+"/fixtures_synthesized.go", fixtureData,
+		permissions.UserReadWrite); err != nil {
+		return errors.Trace(err)
+	}
+	fixtureTestData, err := pkg.BuildFixtureTestFileOutput()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := ioutil.WriteFile(pkg.FixturePath()+"/fixtures_synthesized_test.go", fixtureTestData,
+		permissions.UserReadWrite); err != nil {
+		return errors.Trace(err)
+	}
+
 	// TODO :: 777777 Build this too
 	// var storeTestFile []byte
 	return nil
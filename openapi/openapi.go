@@ -0,0 +1,212 @@
+package openapi
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/espal-digital-development/espal-store-synthesizer/packages"
+	"github.com/espal-digital-development/system/permissions"
+	"github.com/juju/errors"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// Version is the OpenAPI version of the generated document.
+	Version = "3.0.3"
+	// schemaReferencePrefix is the prefix of references to the component schemas.
+	schemaReferencePrefix = "#/components/schemas/"
+)
+
+// OpenAPI package object.
+type OpenAPI struct {
+	path            string
+	includeInternal bool
+	typeNames       map[*packages.Entity]string
+}
+
+// Build writes the OpenAPI document with a component schema per entity.
+func (o *OpenAPI) Build(pkgs []*packages.Package) error {
+	o.typeNames = packages.TypeNames(pkgs)
+	schemas := yaml.MapSlice{}
+	for _, pkg := range pkgs {
+		for _, entity := range pkg.AllEntities() {
+			schemas = append(schemas, yaml.MapItem{Key: o.typeNames[entity], Value: o.entitySchema(entity)})
+		}
+	}
+	document := yaml.MapSlice{
+		{Key: "openapi", Value: Version},
+		{Key: "info", Value: yaml.MapSlice{
+			{Key: "title", Value: "Stores"},
+			{Key: "version", Value: "1.0.0"},
+		}},
+		{Key: "paths", Value: yaml.MapSlice{}},
+		{Key: "components", Value: yaml.MapSlice{
+			{Key: "schemas", Value: schemas},
+		}},
+	}
+	output, err := yaml.Marshal(document)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	output = append([]byte("# Code generated by espal-store-synthesizer. DO NOT EDIT.\n"), output...)
+	return errors.Trace(ioutil.WriteFile(o.path, output, permissions.UserReadWrite))
+}
+
+// entitySchema returns the object schema of the entity's JSON.
+func (o *OpenAPI) entitySchema(entity *packages.Entity) yaml.MapSlice {
+	required := []string{}
+	properties := yaml.MapSlice{}
+	for _, property := range entity.JSONProperties() {
+		relation := hasManyRelation(entity, property)
+		if !property.IsDatabaseField() && relation == nil && !o.includeInternal {
+			continue
+		}
+		var schema yaml.MapSlice
+		if relation != nil && relation.TargetEntity() != nil {
+			schema = yaml.MapSlice{
+				{Key: "type", Value: "array"},
+				{Key: "items", Value: yaml.MapSlice{
+					{Key: "$ref", Value: schemaReferencePrefix + o.typeNames[relation.TargetEntity()]},
+				}},
+			}
+		} else {
+			schema = propertySchema(property)
+		}
+		description := property.Description()
+		if property.BaseType() == "time.Duration" {
+			description = strings.TrimSpace(description + " In nanoseconds.")
+		}
+		if description != "" {
+			schema = append(schema, yaml.MapItem{Key: "description", Value: description})
+		}
+		// Nil slices are encoded as null too
+		if property.IsPointer() || strings.HasPrefix(property.Type(), "[]") {
+			schema = append(schema, yaml.MapItem{Key: "nullable", Value: true})
+		}
+		if entity.IsJSONReadOnly(property) {
+			schema = append(schema, yaml.MapItem{Key: "readOnly", Value: true})
+		}
+		if !property.IsPointer() || property.HasValidationRule(packages.ValidationRequired) {
+			required = append(required, property.JSONName())
+		}
+		properties = append(properties, yaml.MapItem{Key: property.JSONName(), Value: schema})
+	}
+	schema := yaml.MapSlice{{Key: "type", Value: "object"}}
+	if len(required) > 0 {
+		schema = append(schema, yaml.MapItem{Key: "required", Value: required})
+	}
+	return append(schema, yaml.MapItem{Key: "properties", Value: properties})
+}
+
+// propertySchema returns the schema of the property's type, including its validation rules.
+// nolint:gocyclo
+func propertySchema(property *packages.Property) yaml.MapSlice {
+	schema := typeSchema(property.BaseType())
+	if property.BaseType() == "string" && property.SQLType() == "UUID" {
+		schema = set(schema, "format", "uuid")
+	}
+	for _, rule := range property.ValidationRules() {
+		argument, _ := strconv.ParseFloat(rule.Argument(), 64)
+		switch {
+		case rule.Name() == packages.ValidationEmail:
+			schema = set(schema, "format", "email")
+		case rule.Name() == packages.ValidationRequired && property.BaseType() == "string":
+			schema = set(schema, "minLength", 1)
+		case rule.Name() == packages.ValidationMin || rule.Name() == packages.ValidationMax:
+			if property.IsBytes() {
+				// The byte-array length doesn't translate to its base64 length
+				continue
+			}
+			key := "minimum"
+			switch {
+			case property.BaseType() == "string" && rule.Name() == packages.ValidationMin:
+				key = "minLength"
+			case property.BaseType() == "string":
+				key = "maxLength"
+			case strings.HasPrefix(property.BaseType(), "[]") && rule.Name() == packages.ValidationMin:
+				key = "minItems"
+			case strings.HasPrefix(property.BaseType(), "[]"):
+				key = "maxItems"
+			case rule.Name() == packages.ValidationMax:
+				key = "maximum"
+			}
+			schema = set(schema, key, argument)
+		}
+	}
+	return schema
+}
+
+// typeSchema returns the schema of the Go type as the generated MarshalJSON encodes it.
+// nolint:gocyclo
+func typeSchema(goType string) yaml.MapSlice {
+	switch goType {
+	case "string":
+		return yaml.MapSlice{{Key: "type", Value: "string"}}
+	case "bool":
+		return yaml.MapSlice{{Key: "type", Value: "boolean"}}
+	case "int8", "int16", "int32":
+		return yaml.MapSlice{{Key: "type", Value: "integer"}, {Key: "format", Value: "int32"}}
+	case "uint8", "uint16":
+		return yaml.MapSlice{{Key: "type", Value: "integer"}, {Key: "format", Value: "int32"}, {Key: "minimum", Value: 0}}
+	case "int", "int64":
+		return yaml.MapSlice{{Key: "type", Value: "integer"}, {Key: "format", Value: "int64"}}
+	case "uint", "uint32", "uint64":
+		return yaml.MapSlice{{Key: "type", Value: "integer"}, {Key: "format", Value: "int64"}, {Key: "minimum", Value: 0}}
+	case "float32":
+		return yaml.MapSlice{{Key: "type", Value: "number"}, {Key: "format", Value: "float"}}
+	case "float64":
+		return yaml.MapSlice{{Key: "type", Value: "number"}, {Key: "format", Value: "double"}}
+	case "time.Time":
+		return yaml.MapSlice{{Key: "type", Value: "string"}, {Key: "format", Value: "date-time"}}
+	case "time.Duration":
+		return yaml.MapSlice{{Key: "type", Value: "integer"}, {Key: "format", Value: "int64"}}
+	case "[]byte":
+		return yaml.MapSlice{{Key: "type", Value: "string"}, {Key: "format", Value: "byte"}}
+	}
+	if strings.HasPrefix(goType, "[]") {
+		return yaml.MapSlice{
+			{Key: "type", Value: "array"},
+			{Key: "items", Value: typeSchema(strings.TrimPrefix(strings.TrimPrefix(goType, "[]"), "*"))},
+		}
+	}
+	// Types from other packages can be anything
+	return yaml.MapSlice{}
+}
+
+// set sets the schema's key, replacing an existing value.
+func set(schema yaml.MapSlice, key string, value interface{}) yaml.MapSlice {
+	for i := range schema {
+		if schema[i].Key == key {
+			schema[i].Value = value
+			return schema
+		}
+	}
+	return append(schema, yaml.MapItem{Key: key, Value: value})
+}
+
+func hasManyRelation(entity *packages.Entity, property *packages.Property) *packages.Relation {
+	for _, relation := range entity.Relations() {
+		if relation.Property() == property && relation.Kind() == packages.RelationHasMany {
+			return relation
+		}
+	}
+	return nil
+}
+
+// New returns a new instance of OpenAPI that writes the document to the YAML path. The
+// `@synthesize-no-db-field` properties are left out unless includeInternal is set,
+// apart from has-many relations.
+func New(path string, includeInternal bool) (*OpenAPI, error) {
+	if path == "" {
+		return nil, errors.New("the OpenAPI document needs a path")
+	}
+	if extension := filepath.Ext(path); extension != ".yaml" && extension != ".yml" {
+		return nil, errors.Errorf("the OpenAPI document `%s` should be a .yaml or .yml file", path)
+	}
+	return &OpenAPI{
+		path:            path,
+		includeInternal: includeInternal,
+	}, nil
+}
@@ -0,0 +1,117 @@
+package openapi
+
+import (
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"github.com/espal-digital-development/espal-store-synthesizer/packages"
+	"github.com/juju/errors"
+	"gopkg.in/yaml.v2"
+)
+
+type schema struct {
+	Type        string             `yaml:"type"`
+	Format      string             `yaml:"format"`
+	Nullable    bool               `yaml:"nullable"`
+	ReadOnly    bool               `yaml:"readOnly"`
+	Description string             `yaml:"description"`
+	Required    []string           `yaml:"required"`
+	Properties  map[string]*schema `yaml:"properties"`
+	Items       *schema            `yaml:"items"`
+	Reference   string             `yaml:"$ref"`
+}
+
+func buildSchemas(t *testing.T, includeInternal bool) map[string]*schema {
+	t.Helper()
+	pkg := packages.New(&packages.Config{NamingStrategy: packages.NamingAsIs})
+	if err := pkg.BuildMetaData("../testdata/stores/user"); err != nil {
+		t.Fatal(errors.ErrorStack(err))
+	}
+	openAPI, err := New(t.TempDir()+"/openapi.yaml", includeInternal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := openAPI.Build([]*packages.Package{pkg}); err != nil {
+		t.Fatal(errors.ErrorStack(err))
+	}
+	output, err := ioutil.ReadFile(openAPI.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	document := struct {
+		OpenAPI    string `yaml:"openapi"`
+		Components struct {
+			Schemas map[string]*schema `yaml:"schemas"`
+		} `yaml:"components"`
+	}{}
+	if err := yaml.Unmarshal(output, &document); err != nil {
+		t.Fatal(err)
+	}
+	if document.OpenAPI != Version {
+		t.Fatalf("Expected OpenAPI %s, got %s", Version, document.OpenAPI)
+	}
+	return document.Components.Schemas
+}
+
+func TestBuild(t *testing.T) {
+	schemas := buildSchemas(t, false)
+	user := schemas["User"]
+	if user == nil || schemas["Address"] == nil || schemas["UserTranslation"] == nil {
+		t.Fatalf("Expected a schema per entity, got %v", schemas)
+	}
+	expectedRequired := []string{
+		"version", "id", "createdByID", "createdAt", "emailAddress", "avatar", "priority", "addresses",
+	}
+	if !reflect.DeepEqual(user.Required, expectedRequired) {
+		t.Errorf("Expected required %v, got %v", expectedRequired, user.Required)
+	}
+	tests := []struct {
+		property string
+		expected schema
+	}{
+		{"id", schema{Type: "string", Format: "uuid"}},
+		{"version", schema{Type: "integer", Format: "int64"}},
+		{"createdAt", schema{Type: "string", Format: "date-time", ReadOnly: true}},
+		{"emailAddress", schema{Type: "string", Format: "email", Description: "Primary e-mail address."}},
+		{"firstName", schema{Type: "string", Nullable: true}},
+		{"dateOfBirth", schema{Type: "string", Format: "date-time", Nullable: true}},
+		{"avatar", schema{Type: "string", Format: "byte", Nullable: true}},
+		{"priority", schema{Type: "integer", Format: "int32"}},
+		{"addresses", schema{Type: "array", Nullable: true, Items: &schema{Reference: "#/components/schemas/Address"}}},
+	}
+	for _, test := range tests {
+		if !reflect.DeepEqual(user.Properties[test.property], &test.expected) {
+			t.Errorf("Expected %s to be %+v, got %+v", test.property, test.expected, user.Properties[test.property])
+		}
+	}
+	for _, property := range []string{"email", "isDirty", "sessionCount"} {
+		if _, ok := user.Properties[property]; ok {
+			t.Errorf("%s shouldn't be in the schema", property)
+		}
+	}
+
+	internal := buildSchemas(t, true)["User"].Properties["sessionCount"]
+	expected := &schema{Type: "integer", Format: "int64", Description: "Sessions opened since the fetch."}
+	if !reflect.DeepEqual(internal, expected) {
+		t.Errorf("Expected the internal sessionCount to be %+v, got %+v", expected, internal)
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		path  string
+		fails bool
+	}{
+		{"openapi.yaml", false},
+		{"api/openapi.yml", false},
+		{"", true},
+		{"openapi.json", true},
+		{"openapi", true},
+	}
+	for _, test := range tests {
+		if _, err := New(test.path, false); (err != nil) != test.fails {
+			t.Errorf("New(%q) should fail: %t, got %v", test.path, test.fails, err)
+		}
+	}
+}
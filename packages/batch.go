@@ -0,0 +1,191 @@
+package packages
+
+import (
+	"strconv"
+	"strings"
+)
+
+// chunkSize returns how many rows of the given amount of columns fit in one statement.
+func (s *Store) chunkSize(columns int) int {
+	if size := s._package.maxParams() / columns; size > 0 {
+		return size
+	}
+	return 1
+}
+
+// updateProperties returns the database properties that are written on updates.
+func (e *Entity) updateProperties() []*Property {
+	properties := make([]*Property, 0, len(e.properties))
+	for _, property := range e.InsertProperties() {
+		if property.name == "createdByID" {
+			continue
+		}
+		properties = append(properties, property)
+	}
+	return properties
+}
+
+// writeChunkedExec writes the loop that executes the query per chunk of entities. Every entity
+// adds a row of placeholders for the properties, each followed by rowFormat's suffix, to `values`.
+// With a version, every row has to be affected, so the chunks run in one transaction that is rolled
+// back on a conflict, and the entities' versions are only bumped once all of them are written.
+// nolint:funlen
+func (s *Store) writeChunkedExec(body *strings.Builder, chunkSize int, properties []*Property, query string,
+	rowFormat func(property *Property) string, version *Property) {
+	loop := &strings.Builder{}
+	loop.WriteString("\tchunkSize := " + strconv.Itoa(chunkSize) + "\n")
+	loop.WriteString("\tfor start := 0; start < len(entities); start += chunkSize {\n")
+	loop.WriteString("\t\tend := start + chunkSize\n")
+	loop.WriteString("\t\tif end > len(entities) {\n")
+	loop.WriteString("\t\t\tend = len(entities)\n")
+	loop.WriteString("\t\t}\n")
+	loop.WriteString("\t\tvalues := make([]string, 0, end-start)\n")
+	loop.WriteString("\t\tparams := make([]interface{}, 0, (end-start)*" + strconv.Itoa(len(properties)) + ")\n")
+	loop.WriteString("\t\tfor _, entity := range entities[start:end] {\n")
+	loop.WriteString("\t\t\tn := len(params)\n")
+	row := make([]string, 0, len(properties))
+	params := make([]string, 0, len(properties))
+	for i, property := range properties {
+		row = append(row, `$"+strconv.Itoa(n+`+strconv.Itoa(i+1)+`)+"`+rowFormat(property))
+		params = append(params, "entity."+property.name)
+	}
+	loop.WriteString("\t\t\tvalues = append(values, \"(" + strings.Join(row, ", ") + ")\")\n")
+	loop.WriteString("\t\t\tparams = append(params, " + strings.Join(params, ", ") + ")\n")
+	loop.WriteString("\t\t}\n")
+	if version == nil {
+		loop.WriteString("\t\tif _, err := " + query + "; err != nil {\n")
+		loop.WriteString("\t\t\treturn errors.Trace(err)\n")
+		loop.WriteString("\t\t}\n")
+		loop.WriteString("\t}\n")
+		body.WriteString(loop.String())
+		body.WriteString("\treturn nil\n")
+		return
+	}
+	loop.WriteString("\t\tresult, err := " + query + "\n")
+	loop.WriteString("\t\tif err != nil {\n")
+	loop.WriteString("\t\t\treturn errors.Trace(err)\n")
+	loop.WriteString("\t\t}\n")
+	loop.WriteString("\t\taffected, err := result.RowsAffected()\n")
+	loop.WriteString("\t\tif err != nil {\n")
+	loop.WriteString("\t\t\treturn errors.Trace(err)\n")
+	loop.WriteString("\t\t}\n")
+	loop.WriteString("\t\tif affected != int64(end-start) {\n")
+	loop.WriteString("\t\t\treturn errors.Trace(&" + s.VersionConflictErrorName() + "{})\n")
+	loop.WriteString("\t\t}\n")
+	loop.WriteString("\t}\n")
+	loop.WriteString("\treturn nil\n")
+
+	// A store that is already in a transaction writes the chunks in that one
+	body.WriteString("\tvar tx database.Transaction\n")
+	body.WriteString("\tupdater := #UPDATER\n")
+	body.WriteString("\tif _, ok := updater.(" + s.TxDatabaseName() + "); !ok {\n")
+	body.WriteString("\t\tvar err error\n")
+	body.WriteString("\t\tif tx, err = updater.#BEGIN(); err != nil {\n")
+	body.WriteString("\t\t\treturn errors.Trace(err)\n")
+	body.WriteString("\t\t}\n")
+	body.WriteString("\t\tupdater = " + s.TxDatabaseName() + "{Transaction: tx}\n")
+	body.WriteString("\t}\n")
+	body.WriteString("\tif err := func() error {\n")
+	for _, line := range strings.SplitAfter(strings.ReplaceAll(loop.String(), "#UPDATER", "updater"), "\n") {
+		if line != "" {
+			body.WriteString("\t" + line)
+		}
+	}
+	body.WriteString("\t}(); err != nil {\n")
+	body.WriteString("\t\tif tx != nil {\n")
+	body.WriteString("\t\t\tif rollbackErr := tx.Rollback(); rollbackErr != nil {\n")
+	body.WriteString("\t\t\t\treturn errors.Wrap(err, rollbackErr)\n")
+	body.WriteString("\t\t\t}\n")
+	body.WriteString("\t\t}\n")
+	body.WriteString("\t\treturn errors.Trace(err)\n")
+	body.WriteString("\t}\n")
+	body.WriteString("\tif tx != nil {\n")
+	body.WriteString("\t\tif err := tx.Commit(); err != nil {\n")
+	body.WriteString("\t\t\treturn errors.Trace(err)\n")
+	body.WriteString("\t\t}\n")
+	body.WriteString("\t}\n")
+	body.WriteString("\tfor _, entity := range entities {\n")
+	body.WriteString("\t\tentity." + version.name + "++\n")
+	body.WriteString("\t}\n")
+	body.WriteString("\treturn nil\n")
+}
+
+// batchMethods returns the generated InsertMany and UpdateMany methods.
+// nolint:funlen
+func (s *Store) batchMethods() []*storeMethod {
+	entity := s.mainEntity
+	table := quoteIdentifier(entity.TableName())
+	entitiesParameter := []*FunctionParameter{{name: "entities", _type: "[]*" + entity.Name()}}
+	methods := []*storeMethod{}
+
+	if properties := entity.InsertProperties(); len(properties) > 0 &&
+		s.databaseField("inserterDatabase", "updaterDatabase") != "" {
+		columns := make([]string, 0, len(properties))
+		for _, property := range properties {
+			columns = append(columns, quoteIdentifier(property.ColumnName()))
+		}
+		chunkSize := s.chunkSize(len(properties))
+		body := &strings.Builder{}
+		s.writeChunkedExec(body, chunkSize, properties, "#INSERTER.Exec#CTX(`INSERT INTO "+table+" ("+
+			strings.Join(columns, ", ")+") VALUES `+strings.Join(values, \", \"), params...)",
+			func(*Property) string { return "" }, nil)
+		methods = append(methods, &storeMethod{
+			function: &Function{
+				name:         "InsertMany",
+				parameters:   entitiesParameter,
+				returnValues: []*FunctionReturnValue{{_type: "error"}},
+			},
+			comment: "InsertMany inserts the entities with multi-row INSERTs of at most " + strconv.Itoa(chunkSize) +
+				" rows each.",
+			body:         body.String(),
+			imports:      []string{"strconv", "strings"},
+			usesDatabase: true,
+		})
+	}
+
+	id := entity.Property("id")
+	if properties := entity.updateProperties(); id != nil && id.IsDatabaseField() && len(properties) > 0 &&
+		s.databaseField("updaterDatabase") != "" {
+		alias := entity.TableAlias()
+		version := entity.VersionProperty()
+		properties = append([]*Property{id}, properties...)
+		columns := make([]string, 0, len(properties))
+		updates := make([]string, 0, len(properties))
+		condition := alias + "." + quoteIdentifier(id.ColumnName()) + " = v." + quoteIdentifier(id.ColumnName())
+		for _, property := range properties {
+			column := quoteIdentifier(property.ColumnName())
+			columns = append(columns, column)
+			switch property {
+			case id:
+			case version:
+				updates = append(updates, column+" = "+alias+"."+column+" + 1")
+				condition += " AND " + alias + "." + column + " = v." + column
+			default:
+				updates = append(updates, column+" = v."+column)
+			}
+		}
+		if updatedAt := entity.Property("updatedAt"); updatedAt != nil && updatedAt.IsDatabaseField() {
+			updates = append(updates, quoteIdentifier(updatedAt.ColumnName())+" = NOW()")
+		}
+		chunkSize := s.chunkSize(len(properties))
+		body := &strings.Builder{}
+		// The VALUES list has no column types of its own, so every parameter gets cast to its column's type
+		s.writeChunkedExec(body, chunkSize, properties, "#UPDATER.Exec#CTX(`UPDATE "+table+" AS "+alias+" SET "+
+			strings.Join(updates, ", ")+" FROM (VALUES `+strings.Join(values, \", \")+`) AS v("+
+			strings.Join(columns, ", ")+") WHERE "+condition+"`, params...)",
+			func(property *Property) string { return "::" + property.SQLType() }, version)
+		methods = append(methods, &storeMethod{
+			function: &Function{
+				name:         "UpdateMany",
+				parameters:   entitiesParameter,
+				returnValues: []*FunctionReturnValue{{_type: "error"}},
+			},
+			comment: "UpdateMany updates the entities by their ID with multi-row UPDATEs of at most " +
+				strconv.Itoa(chunkSize) + " rows each.",
+			body:         body.String(),
+			imports:      []string{"strconv", "strings"},
+			usesDatabase: true,
+		})
+	}
+	return methods
+}
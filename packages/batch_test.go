@@ -0,0 +1,32 @@
+package packages
+
+import (
+	"testing"
+)
+
+func TestChunkSize(t *testing.T) {
+	tests := []struct {
+		maxParams int
+		columns   int
+		expected  int
+	}{
+		{0, 10, DefaultMaxParams / 10},
+		{-1, 10, DefaultMaxParams / 10},
+		{100, 10, 10},
+		{100, 7, 14},
+		{100, 100, 1},
+		{5, 10, 1},
+	}
+	for _, test := range tests {
+		store := &Store{_package: &Package{config: &Config{MaxParams: test.maxParams}}}
+		if actual := store.chunkSize(test.columns); actual != test.expected {
+			t.Errorf("chunkSize(%d) with %d max params = %d, expected %d", test.columns, test.maxParams, actual,
+				test.expected)
+		}
+	}
+
+	store := &Store{_package: &Package{}}
+	if actual := store.chunkSize(5); actual != DefaultMaxParams/5 {
+		t.Errorf("chunkSize(5) without config = %d, expected %d", actual, DefaultMaxParams/5)
+	}
+}
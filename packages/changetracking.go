@@ -0,0 +1,202 @@
+package packages
+
+import (
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// changedFieldsProperty is the entity field that holds the changes of entities that track them.
+const changedFieldsProperty = "changedFields"
+
+// TracksChanges returns if the entity opted in to change tracking with a `// @synthesize-track-changes`
+// line. Its setters then record which fields they changed, so only those can be updated.
+func (e *Entity) TracksChanges() bool {
+	_, ok := e.Annotation("track-changes")
+	return ok
+}
+
+// extractChangedFieldsProperty takes the field that holds the changes out of the entity's properties,
+// as it's bookkeeping of the synthesized code rather than part of the model.
+func (e *Entity) extractChangedFieldsProperty() error {
+	if !e.TracksChanges() {
+		return nil
+	}
+	for i, property := range e.properties {
+		if property.name != changedFieldsProperty {
+			continue
+		}
+		if property._type != "map["+e.FieldTypeName()+"]bool" {
+			return errors.Errorf("`%s.%s` must be a `map[%s]bool`", e.name, changedFieldsProperty, e.FieldTypeName())
+		}
+		e.properties = append(e.properties[:i], e.properties[i+1:]...)
+		return nil
+	}
+	return errors.Errorf("`%s` tracks changes and needs a `%s map[%s]bool` field", e.name, changedFieldsProperty,
+		e.FieldTypeName())
+}
+
+// isFieldProperty returns if the property is one of the entity's database fields.
+func (e *Entity) isFieldProperty(property *Property) bool {
+	for _, candidate := range e.DatabaseProperties() {
+		if candidate == property {
+			return true
+		}
+	}
+	return false
+}
+
+// writeChangeTracking writes the methods that record, list and reset the entity's changes.
+func (e *Entity) writeChangeTracking(output io.StringWriter) {
+	v := e.VariableName()
+	fieldType := e.FieldTypeName()
+	output.WriteString("\n")
+	output.WriteString("func (" + v + " *" + e.name + ") markChanged(field " + fieldType + ") {\n")
+	output.WriteString("\tif " + v + "." + changedFieldsProperty + " == nil {\n")
+	output.WriteString("\t\t" + v + "." + changedFieldsProperty + " = map[" + fieldType + "]bool{}\n")
+	output.WriteString("\t}\n")
+	output.WriteString("\t" + v + "." + changedFieldsProperty + "[field] = true\n")
+	output.WriteString("}\n")
+
+	output.WriteString("\n")
+	output.WriteString("// ChangedFields returns the fields that were set since the entity was created, fetched or\n")
+	output.WriteString("// had its changes reset, in scan order.\n")
+	output.WriteString("func (" + v + " *" + e.name + ") ChangedFields() []" + fieldType + " {\n")
+	output.WriteString("\tfields := make([]" + fieldType + ", 0, len(" + v + "." + changedFieldsProperty + "))\n")
+	output.WriteString("\tfor field := " + fieldType + "(0); field < " + strconv.Itoa(len(e.DatabaseProperties())) +
+		"; field++ {\n")
+	output.WriteString("\t\tif " + v + "." + changedFieldsProperty + "[field] {\n")
+	output.WriteString("\t\t\tfields = append(fields, field)\n")
+	output.WriteString("\t\t}\n")
+	output.WriteString("\t}\n")
+	output.WriteString("\treturn fields\n")
+	output.WriteString("}\n")
+
+	output.WriteString("\n")
+	output.WriteString("// ResetChanges forgets the recorded changes.\n")
+	output.WriteString("func (" + v + " *" + e.name + ") ResetChanges() {\n")
+	output.WriteString("\t" + v + "." + changedFieldsProperty + " = nil\n")
+	output.WriteString("}\n")
+
+	output.WriteString("\n")
+	output.WriteString("func (" + v + " *" + e.name + ") fieldValue(field " + fieldType + ") interface{} {\n")
+	output.WriteString("\tswitch field {\n")
+	for _, property := range e.DatabaseProperties() {
+		output.WriteString("\tcase " + fieldType + property.GetterName() + ":\n")
+		output.WriteString("\t\treturn " + v + "." + property.name + "\n")
+	}
+	output.WriteString("\t}\n")
+	output.WriteString("\treturn nil\n")
+	output.WriteString("}\n")
+}
+
+// changeTrackingMethods returns the generated UpdateChanged method.
+func (s *Store) changeTrackingMethods() []*storeMethod {
+	entity := s.mainEntity
+	id := entity.Property("id")
+	if !entity.TracksChanges() || id == nil || !id.IsDatabaseField() || s.databaseField("updaterDatabase") == "" {
+		return nil
+	}
+	body := &strings.Builder{}
+	body.WriteString("\tfields := entity.ChangedFields()\n")
+	body.WriteString("\tif len(fields) == 0 {\n")
+	body.WriteString("\t\treturn nil\n")
+	body.WriteString("\t}\n")
+	body.WriteString("\tsets := make([]string, 0, len(fields)+1)\n")
+	body.WriteString("\tparams := make([]interface{}, 0, len(fields)+1)\n")
+	version := entity.VersionProperty()
+	body.WriteString("\tfor _, field := range fields {\n")
+	if version != nil {
+		body.WriteString("\t\tif field == " + entity.FieldTypeName() + version.GetterName() + " {\n")
+		body.WriteString("\t\t\tcontinue\n")
+		body.WriteString("\t\t}\n")
+	}
+	body.WriteString("\t\tparams = append(params, entity.fieldValue(field))\n")
+	body.WriteString("\t\tsets = append(sets, `\"`+field.Column()+`\" = $`+strconv.Itoa(len(params)))\n")
+	body.WriteString("\t}\n")
+	if updatedAt := entity.Property("updatedAt"); updatedAt != nil && updatedAt.IsDatabaseField() {
+		body.WriteString("\tif !entity." + changedFieldsProperty + "[" + entity.FieldTypeName() +
+			updatedAt.GetterName() + "] {\n")
+		body.WriteString("\t\tsets = append(sets, `" + quoteIdentifier(updatedAt.ColumnName()) + " = NOW()`)\n")
+		body.WriteString("\t}\n")
+	}
+	query := "`UPDATE " + quoteIdentifier(entity.TableName()) + " SET `+strings.Join(sets, \", \")+` WHERE " +
+		quoteIdentifier(id.ColumnName()) + " = $`+strconv.Itoa(len(params))"
+	if version != nil {
+		// The version is the last parameter, following the id
+		query = "`UPDATE " + quoteIdentifier(entity.TableName()) + " SET `+strings.Join(sets, \", \")+` WHERE " +
+			quoteIdentifier(id.ColumnName()) + " = $`+strconv.Itoa(len(params)-1)+` AND " +
+			quoteIdentifier(version.ColumnName()) + " = $`+strconv.Itoa(len(params))"
+	}
+	body.WriteString("\tparams = append(params, entity." + id.name + ")\n")
+	if version == nil {
+		body.WriteString("\tif _, err := #UPDATER.Exec#CTX(" + query + ", params...); err != nil {\n")
+		body.WriteString("\t\treturn errors.Trace(err)\n")
+		body.WriteString("\t}\n")
+	} else {
+		column := quoteIdentifier(version.ColumnName())
+		body.WriteString("\tsets = append(sets, `" + column + " = " + column + " + 1`)\n")
+		body.WriteString("\tparams = append(params, entity." + version.name + ")\n")
+		body.WriteString("\tresult, err := #UPDATER.Exec#CTX(" + query + ", params...)\n")
+		body.WriteString("\tif err != nil {\n")
+		body.WriteString("\t\treturn errors.Trace(err)\n")
+		body.WriteString("\t}\n")
+		body.WriteString("\taffected, err := result.RowsAffected()\n")
+		body.WriteString("\tif err != nil {\n")
+		body.WriteString("\t\treturn errors.Trace(err)\n")
+		body.WriteString("\t}\n")
+		body.WriteString("\tif affected == 0 {\n")
+		body.WriteString("\t\tid := entity." + id.name + "\n")
+		body.WriteString("\t\treturn errors.Trace(&" + s.VersionConflictErrorName() + "{ID: &id})\n")
+		body.WriteString("\t}\n")
+		body.WriteString("\tentity." + version.name + "++\n")
+	}
+	body.WriteString("\tentity.ResetChanges()\n")
+	body.WriteString("\treturn nil\n")
+	return []*storeMethod{
+		{
+			function: &Function{
+				name:         "UpdateChanged",
+				parameters:   []*FunctionParameter{{name: "entity", _type: "*" + entity.Name()}},
+				returnValues: []*FunctionReturnValue{{_type: "error"}},
+			},
+			comment: "UpdateChanged updates only the changed columns of the " + entity.Name() +
+				" and resets its changes.",
+			body:         body.String(),
+			imports:      []string{"strconv", "strings"},
+			usesDatabase: true,
+		},
+	}
+}
+
+// writeChangeTrackingTest writes the test that setters record their changes.
+func (e *Entity) writeChangeTrackingTest(output io.StringWriter) {
+	var property *Property
+	for _, candidate := range e.DatabaseProperties() {
+		if candidate.name != "id" {
+			property = candidate
+			break
+		}
+	}
+	if property == nil {
+		return
+	}
+	v := e.TestVariableName()
+	output.WriteString("func Test" + e.name + "ChangedFields(t *testing.T) {\n")
+	output.WriteString("\t" + v + " := " + e.PackageName() + "." + e.PublicNewFunctionName() + "()\n")
+	output.WriteString("\tif len(" + v + ".ChangedFields()) != 0 {\n")
+	output.WriteString("\t\tt.Fatal(\"New entities shouldn't have changes\")\n")
+	output.WriteString("\t}\n")
+	output.WriteString("\t" + v + "." + property.SetterName() + "(" + v + "." + property.GetterName() + "())\n")
+	output.WriteString("\tif fields := " + v + ".ChangedFields(); len(fields) != 1 || fields[0] != " +
+		e.PackageName() + "." + e.FieldTypeName() + property.GetterName() + " {\n")
+	output.WriteString("\t\tt.Fatalf(\"Expected only " + property.name + " to be changed, got %v\", fields)\n")
+	output.WriteString("\t}\n")
+	output.WriteString("\t" + v + ".ResetChanges()\n")
+	output.WriteString("\tif len(" + v + ".ChangedFields()) != 0 {\n")
+	output.WriteString("\t\tt.Fatal(\"Changes should be reset\")\n")
+	output.WriteString("\t}\n")
+	output.WriteString("}\n\n")
+}
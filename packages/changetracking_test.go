@@ -0,0 +1,51 @@
+package packages
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestChangeTrackingGeneration(t *testing.T) {
+	outputs := buildTestOutputs(t, loadTestPackage(t, "user"))
+	tests := []struct {
+		file     string
+		expected []string
+	}{
+		{"User_synthesized.go", []string{
+			"func (u *User) SetEmail(email string) {\n\tu.email = email\n\tu.markChanged(UserFieldEmail)\n}",
+			"func (u *User) markChanged(field UserField) {\n" +
+				"\tif u.changedFields == nil {\n" +
+				"\t\tu.changedFields = map[UserField]bool{}\n" +
+				"\t}\n" +
+				"\tu.changedFields[field] = true\n" +
+				"}",
+			"func (u *User) ChangedFields() []UserField {",
+			"\tfor field := UserField(0); field < 12; field++ {\n",
+			"func (u *User) ResetChanges() {\n\tu.changedFields = nil\n}",
+			"\tChangedFields() []UserField\n\tResetChanges()\n",
+		}},
+		{"store_synthesized.go", []string{
+			"func (s *UsersStore) UpdateChanged(entity *User) error {",
+			"\t\tparams = append(params, entity.fieldValue(field))\n" +
+				"\t\tsets = append(sets, `\"`+field.Column()+`\" = $`+strconv.Itoa(len(params)))\n",
+			"\tif !entity.changedFields[UserFieldUpdatedAt] {\n" +
+				"\t\tsets = append(sets, `\"updatedAt\" = NOW()`)\n",
+			"\tentity.ResetChanges()\n",
+		}},
+	}
+	for _, test := range tests {
+		for _, expected := range test.expected {
+			if !strings.Contains(outputs[test.file], expected) {
+				t.Errorf("%s doesn't contain %s", test.file, expected)
+			}
+		}
+	}
+
+	// Entities that don't opt in keep their plain setters
+	outputs = buildTestOutputs(t, loadTestPackage(t, "order"))
+	for _, file := range []string{"Order_synthesized.go", "store_synthesized.go"} {
+		if strings.Contains(outputs[file], "markChanged") || strings.Contains(outputs[file], "UpdateChanged") {
+			t.Errorf("%s shouldn't track changes\n%s", file, outputs[file])
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package packages
+
+import (
+	"io"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// FieldChangeTypeName is the name of the type Diff reports changes with. It's written once per package,
+// along with the main entity.
+const FieldChangeTypeName = "FieldChange"
+
+// comparableProperties returns the properties that Equal and Diff compare, which are the ones
+// backed by columns, including the joined creator columns.
+func (e *Entity) comparableProperties() []*Property {
+	return append(e.DatabaseProperties(), e.CreatorProperties()...)
+}
+
+// comparesBytes returns if Equal and Diff compare any of the properties with bytes.Equal.
+func (e *Entity) comparesBytes() bool {
+	for _, property := range e.comparableProperties() {
+		if strings.Contains(equalExpression(property, "a", "b"), "bytes.Equal(") {
+			return true
+		}
+	}
+	return false
+}
+
+// checkComparableProperties returns an error for properties Equal and Diff can't compare, which
+// are the maps, functions, channels and slices of slices other than [][]byte.
+func (e *Entity) checkComparableProperties() error {
+	for _, property := range e.comparableProperties() {
+		if !isComparableType(property._type) {
+			return errors.Errorf("`%s.%s` of type `%s` can't be compared", e.name, property.name, property._type)
+		}
+	}
+	return nil
+}
+
+// isComparableType returns if equalTypeExpression can compare values of the given type.
+func isComparableType(_type string) bool {
+	switch {
+	case _type == "[]byte":
+		return true
+	case strings.HasPrefix(_type, "map["), strings.HasPrefix(_type, "func("), strings.HasPrefix(_type, "chan "),
+		strings.HasPrefix(_type, "<-chan "), strings.HasPrefix(_type, "*[]"), strings.HasPrefix(_type, "*map["):
+		return false
+	case strings.HasPrefix(_type, "[]"):
+		element := strings.TrimPrefix(_type, "[]")
+		return element == "[]byte" || (!strings.HasPrefix(element, "[]") && isComparableType(element))
+	}
+	return true
+}
+
+// equalExpression returns the Go expression that reports if the property is equal on both variables.
+func equalExpression(property *Property, a string, b string) string {
+	return equalTypeExpression(property._type, a+"."+property.name, b+"."+property.name)
+}
+
+// equalTypeExpression returns the Go expression that reports if a and b of the given type are equal.
+// Slices are compared element-wise in a function literal that's indented for Equal's and Diff's
+// if statements.
+func equalTypeExpression(_type string, a string, b string) string {
+	switch {
+	case _type == "[]byte":
+		return "bytes.Equal(" + a + ", " + b + ")"
+	case _type == "time.Time":
+		return a + ".Equal(" + b + ")"
+	case _type == "*time.Time":
+		return "(" + a + " == nil) == (" + b + " == nil) && (" + a + " == nil || " + a + ".Equal(*" + b + "))"
+	case strings.HasPrefix(_type, "*"):
+		return "(" + a + " == nil) == (" + b + " == nil) && (" + a + " == nil || *" + a + " == *" + b + ")"
+	case strings.HasPrefix(_type, "[]"):
+		return "func() bool {\n" +
+			"\t\tif len(" + a + ") != len(" + b + ") {\n" +
+			"\t\t\treturn false\n" +
+			"\t\t}\n" +
+			"\t\tfor i := range " + a + " {\n" +
+			"\t\t\tif !(" + equalTypeExpression(strings.TrimPrefix(_type, "[]"), a+"[i]", b+"[i]") + ") {\n" +
+			"\t\t\t\treturn false\n" +
+			"\t\t\t}\n" +
+			"\t\t}\n" +
+			"\t\treturn true\n" +
+			"\t}()"
+	}
+	return a + " == " + b
+}
+
+// cloneStatement returns the statement that deep-copies the property from the entity in variable
+// to the clone, or an empty string if the shallow copy already did.
+func (e *Entity) cloneStatement(property *Property, variable string) string {
+	source := variable + "." + property.name
+	target := "clone." + property.name
+	switch {
+	case property.IsPointer():
+		return "\tif " + source + " != nil {\n" +
+			"\t\t" + property.name + " := *" + source + "\n" +
+			"\t\t" + target + " = &" + property.name + "\n" +
+			"\t}\n"
+	case strings.HasPrefix(property._type, "[]*"):
+		for _, relation := range e.relations {
+			if relation.property == property && relation.targetEntity != nil {
+				return "\tif " + source + " != nil {\n" +
+					"\t\t" + target + " = make(" + property._type + ", len(" + source + "))\n" +
+					"\t\tfor i := range " + source + " {\n" +
+					"\t\t\t" + target + "[i] = " + source + "[i].Clone()\n" +
+					"\t\t}\n" +
+					"\t}\n"
+			}
+		}
+		fallthrough
+	case strings.HasPrefix(property._type, "[]"):
+		return "\tif " + source + " != nil {\n" +
+			"\t\t" + target + " = append(" + property._type + "{}, " + source + "...)\n" +
+			"\t}\n"
+	}
+	return ""
+}
+
+// writeCompareInterfaceMethods writes the Clone, Equal and Diff signatures for the entity's interface.
+func (e *Entity) writeCompareInterfaceMethods(output io.StringWriter) {
+	output.WriteString("\tClone() *" + e.name + "\n")
+	output.WriteString("\tEqual(other *" + e.name + ") bool\n")
+	output.WriteString("\tDiff(other *" + e.name + ") []" + FieldChangeTypeName + "\n")
+}
+
+// writeCompareMethods writes the entity's Clone, Equal and Diff methods, and the FieldChange type
+// for the package's main entity.
+// nolint:funlen
+func (e *Entity) writeCompareMethods(output io.StringWriter) {
+	v := e.VariableName()
+	if e.IsPrimaryEntity() {
+		output.WriteString("\n")
+		output.WriteString("// " + FieldChangeTypeName + " is a property that differs between two entities.\n")
+		output.WriteString("type " + FieldChangeTypeName + " struct {\n")
+		output.WriteString("\tField string\n")
+		output.WriteString("\tOld   interface{}\n")
+		output.WriteString("\tNew   interface{}\n")
+		output.WriteString("}\n")
+	}
+
+	output.WriteString("\n")
+	output.WriteString("// Clone returns a deep copy of the entity.\n")
+	output.WriteString("func (" + v + " *" + e.name + ") Clone() *" + e.name + " {\n")
+	output.WriteString("\tclone := *" + v + "\n")
+	for _, property := range e.properties {
+		output.WriteString(e.cloneStatement(property, v))
+	}
+	if e.TracksChanges() {
+		output.WriteString("\tif " + v + "." + changedFieldsProperty + " != nil {\n")
+		output.WriteString("\t\tclone." + changedFieldsProperty + " = make(map[" + e.FieldTypeName() + "]bool, len(" +
+			v + "." + changedFieldsProperty + "))\n")
+		output.WriteString("\t\tfor field, changed := range " + v + "." + changedFieldsProperty + " {\n")
+		output.WriteString("\t\t\tclone." + changedFieldsProperty + "[field] = changed\n")
+		output.WriteString("\t\t}\n")
+		output.WriteString("\t}\n")
+	}
+	output.WriteString("\treturn &clone\n")
+	output.WriteString("}\n")
+
+	output.WriteString("\n")
+	output.WriteString("// Equal returns if the other entity has the same column values.\n")
+	output.WriteString("func (" + v + " *" + e.name + ") Equal(other *" + e.name + ") bool {\n")
+	output.WriteString("\tif " + v + " == nil || other == nil {\n")
+	output.WriteString("\t\treturn " + v + " == other\n")
+	output.WriteString("\t}\n")
+	for _, property := range e.comparableProperties() {
+		output.WriteString("\tif !(" + equalExpression(property, v, "other") + ") {\n")
+		output.WriteString("\t\treturn false\n")
+		output.WriteString("\t}\n")
+	}
+	output.WriteString("\treturn true\n")
+	output.WriteString("}\n")
+
+	output.WriteString("\n")
+	output.WriteString("// Diff returns the column values that differ from the other entity, with the other's values as New.\n")
+	output.WriteString("func (" + v + " *" + e.name + ") Diff(other *" + e.name + ") []" + FieldChangeTypeName + " {\n")
+	output.WriteString("\tchanges := []" + FieldChangeTypeName + "{}\n")
+	for _, property := range e.comparableProperties() {
+		output.WriteString("\tif !(" + equalExpression(property, v, "other") + ") {\n")
+		output.WriteString("\t\tchanges = append(changes, " + FieldChangeTypeName + "{Field: \"" + property.name +
+			"\", Old: " + v + "." + property.name + ", New: other." + property.name + "})\n")
+		output.WriteString("\t}\n")
+	}
+	output.WriteString("\treturn changes\n")
+	output.WriteString("}\n")
+}
+
+// writeCompareTest writes the test that clones are equal and changes show up in the diff.
+func (e *Entity) writeCompareTest(output io.StringWriter) {
+	v := e.TestVariableName()
+	output.WriteString("func Test" + e.name + "CloneEqualDiff(t *testing.T) {\n")
+	output.WriteString("\t" + v + " := " + e.PackageName() + "." + e.PublicNewFunctionName() + "()\n")
+	output.WriteString("\tclone := " + v + ".Clone()\n")
+	output.WriteString("\tif !" + v + ".Equal(clone) || len(" + v + ".Diff(clone)) != 0 {\n")
+	output.WriteString("\t\tt.Fatal(\"Clones should be equal\")\n")
+	output.WriteString("\t}\n")
+	for _, property := range e.comparableProperties() {
+		if property._type != "string" || property.name == "id" {
+			continue
+		}
+		output.WriteString("\tclone." + property.SetterName() + "(\"changed\")\n")
+		output.WriteString("\tif " + v + ".Equal(clone) {\n")
+		output.WriteString("\t\tt.Fatal(\"Changed clones shouldn't be equal\")\n")
+		output.WriteString("\t}\n")
+		output.WriteString("\tif diff := " + v + ".Diff(clone); len(diff) != 1 || diff[0].Field != \"" +
+			property.name + "\" {\n")
+		output.WriteString("\t\tt.Fatalf(\"Expected only " + property.name + " in the diff, got %v\", diff)\n")
+		output.WriteString("\t}\n")
+		break
+	}
+	output.WriteString("}\n\n")
+}
@@ -0,0 +1,41 @@
+package packages
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompareGeneration(t *testing.T) {
+	output := buildTestOutputs(t, loadTestPackage(t, "user"))["User_synthesized.go"]
+	equal := output[strings.Index(output, "func (u *User) Equal("):strings.Index(output, "func (u *User) Diff(")]
+	expected := []string{
+		"type FieldChange struct {\n\tField string\n\tOld   interface{}\n\tNew   interface{}\n}",
+		"func (u *User) Clone() *User {\n\tclone := *u\n",
+		"\tif u.firstName != nil {\n\t\tfirstName := *u.firstName\n\t\tclone.firstName = &firstName\n\t}\n",
+		"\tif u.avatar != nil {\n\t\tclone.avatar = append([]byte{}, u.avatar...)\n\t}\n",
+		"\t\tfor i := range u.addresses {\n\t\t\tclone.addresses[i] = u.addresses[i].Clone()\n\t\t}\n",
+		"\t\tclone.changedFields = make(map[UserField]bool, len(u.changedFields))\n",
+		"\tif u == nil || other == nil {\n\t\treturn u == other\n\t}\n",
+		"\tif !(u.email == other.email) {\n\t\treturn false\n\t}\n",
+		"\tif !(u.createdAt.Equal(other.createdAt)) {\n",
+		"\tif !((u.dateOfBirth == nil) == (other.dateOfBirth == nil) && " +
+			"(u.dateOfBirth == nil || u.dateOfBirth.Equal(*other.dateOfBirth))) {\n",
+		"\tif !((u.firstName == nil) == (other.firstName == nil) && " +
+			"(u.firstName == nil || *u.firstName == *other.firstName)) {\n",
+		"\tif !(bytes.Equal(u.avatar, other.avatar)) {\n",
+		"func (u *User) Diff(other *User) []FieldChange {",
+		"\t\tchanges = append(changes, FieldChange{Field: \"priority\", Old: u.priority, New: other.priority})\n",
+		`"bytes"`,
+	}
+	for _, expected := range expected {
+		if !strings.Contains(output, expected) {
+			t.Errorf("User doesn't contain %s", expected)
+		}
+	}
+	// Only the columns are compared
+	for _, name := range []string{"isDirty", "addresses", "changedFields"} {
+		if strings.Contains(equal, "u."+name) {
+			t.Errorf("Equal shouldn't compare %s\n%s", name, equal)
+		}
+	}
+}
@@ -3,7 +3,7 @@ package packages
 import (
 	"bytes"
 	"io"
-	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/juju/errors"
@@ -14,6 +14,13 @@ type Import struct {
 	path string
 }
 
+// creatorColumn describes where a creator property is joined in from.
+type creatorColumn struct {
+	joinAlias  string
+	foreignKey string
+	property   string
+}
+
 // Entity information object.
 type Entity struct {
 	_package      *Package
@@ -31,6 +38,9 @@ type Entity struct {
 	hasPublicNewMethod                    bool
 	skipPropertiesForInterface            map[string]bool
 	skipPropertiesForTranslationInterface map[string]bool
+	creatorProperties                     map[string]*creatorColumn
+	annotations                           map[string]string
+	relations                             []*Relation
 }
 
 // IsPrimaryEntity returns if this entity is the package's primary e.
@@ -48,6 +58,16 @@ func (e *Entity) ContainsBytesType() bool {
 	return false
 }
 
+// DatabaseContainsBytesType returns if the entity contains a database property of the byte-array type.
+func (e *Entity) DatabaseContainsBytesType() bool {
+	for _, property := range e.DatabaseProperties() {
+		if property.IsBytes() {
+			return true
+		}
+	}
+	return false
+}
+
 // HasOptionalCreator return if the entity has a creatorID field that is optional.
 func (e *Entity) HasOptionalCreator() bool {
 	for _, property := range e.properties {
@@ -63,6 +83,182 @@ func (e *Entity) IsTranslation() bool {
 	return strings.HasSuffix(e.name, "Translation")
 }
 
+// IsExported returns if the entity's struct is exported.
+func (e *Entity) IsExported() bool {
+	return e.name == strings.Title(e.name)
+}
+
+// Annotation returns the argument of the entity's `// @synthesize-<name>` annotation and if it's present at all.
+func (e *Entity) Annotation(name string) (string, bool) {
+	value, ok := e.annotations[name]
+	return value, ok
+}
+
+// NamingStrategy returns the entity's naming strategy, which can be overridden
+// with the `// @synthesize-naming` annotation.
+func (e *Entity) NamingStrategy() (NamingStrategy, error) {
+	if name, ok := e.Annotation("naming"); ok {
+		namingStrategy, err := ParseNamingStrategy(name)
+		if err != nil {
+			return "", errors.Annotatef(err, "entity `%s`", e.name)
+		}
+		return namingStrategy, nil
+	}
+	if e._package.config == nil || e._package.config.NamingStrategy == "" {
+		return NamingAsIs, nil
+	}
+	return e._package.config.NamingStrategy, nil
+}
+
+// TableName returns the entity's table name. A hand-written TableName method
+// wins over the `// @synthesize-table` annotation, which wins over the naming strategy.
+func (e *Entity) TableName() string {
+	if e.tableName != "" {
+		return e.tableName
+	}
+	if tableName, ok := e.Annotation("table"); ok && tableName != "" {
+		return tableName
+	}
+	// The strategy was already validated when the entity was parsed
+	namingStrategy, _ := e.NamingStrategy()
+	return namingStrategy.TableName(e.name)
+}
+
+// TableAlias returns the entity's table alias for use in queries.
+func (e *Entity) TableAlias() string {
+	if e.tableAlias != "" {
+		return e.tableAlias
+	}
+	return strings.ToLower(e._package.reLowerCase.ReplaceAllString(e.interfaceName, ""))
+}
+
+// Properties returns all the entity's properties.
+func (e *Entity) Properties() []*Property {
+	return e.properties
+}
+
+// Property returns the entity's property with the given name or nil if it doesn't exist.
+func (e *Entity) Property(name string) *Property {
+	for _, property := range e.properties {
+		if property.name == name {
+			return property
+		}
+	}
+	return nil
+}
+
+// idType returns the base type of the entity's id property, or string if it doesn't have one.
+func (e *Entity) idType() string {
+	if id := e.Property("id"); id != nil {
+		return id.BaseType()
+	}
+	return "string"
+}
+
+// Relations returns the entity's annotated relations.
+func (e *Entity) Relations() []*Relation {
+	return e.relations
+}
+
+// DatabaseProperties returns the properties that are columns of the entity's
+// own table in the order they are scanned. The creator properties are excluded
+// as they're joined in from the user table.
+func (e *Entity) DatabaseProperties() []*Property {
+	properties := make([]*Property, 0, len(e.properties))
+	for _, property := range e.properties {
+		if _, ok := e.creatorProperties[property.Name()]; ok {
+			continue
+		}
+		if !property.IsDatabaseField() {
+			continue
+		}
+		properties = append(properties, property)
+	}
+	return properties
+}
+
+// CreatorProperties returns the properties that are joined in from the user
+// table when fetching with creators, in the order they are scanned.
+func (e *Entity) CreatorProperties() []*Property {
+	properties := make([]*Property, 0, len(e.creatorProperties))
+	for _, property := range e.properties {
+		if _, ok := e.creatorProperties[property.Name()]; ok {
+			properties = append(properties, property)
+		}
+	}
+	return properties
+}
+
+// SelectColumns returns the comma separated and alias prefixed database columns in scan order.
+func (e *Entity) SelectColumns() string {
+	columns := strings.Builder{}
+	for i, property := range e.DatabaseProperties() {
+		if i > 0 {
+			columns.WriteString(", ")
+		}
+		columns.WriteString(e.TableAlias() + "." + quoteIdentifier(property.ColumnName()))
+	}
+	return columns.String()
+}
+
+// CreatorSelectColumns returns the comma separated and alias prefixed creator columns in scan order.
+func (e *Entity) CreatorSelectColumns() string {
+	namingStrategy := e.userNamingStrategy()
+	columns := strings.Builder{}
+	for i, property := range e.CreatorProperties() {
+		if i > 0 {
+			columns.WriteString(", ")
+		}
+		creator := e.creatorProperties[property.Name()]
+		columns.WriteString(creator.joinAlias + "." + quoteIdentifier(namingStrategy.ColumnName(creator.property)))
+	}
+	return columns.String()
+}
+
+// CreatorsJoin returns the LEFT JOIN clauses that provide the columns of CreatorSelectColumns.
+func (e *Entity) CreatorsJoin() string {
+	namingStrategy := e.userNamingStrategy()
+	userTable := quoteIdentifier(namingStrategy.TableName("User"))
+	userID := quoteIdentifier(namingStrategy.ColumnName("id"))
+	joined := map[string]bool{}
+	joins := make([]string, 0, len(e.creatorProperties))
+	for _, property := range e.CreatorProperties() {
+		creator := e.creatorProperties[property.Name()]
+		if joined[creator.joinAlias] {
+			continue
+		}
+		joined[creator.joinAlias] = true
+		foreignKey := creator.foreignKey
+		for _, databaseProperty := range e.DatabaseProperties() {
+			if databaseProperty.Name() == creator.foreignKey {
+				foreignKey = databaseProperty.ColumnName()
+				break
+			}
+		}
+		joins = append(joins, "LEFT JOIN "+userTable+" "+creator.joinAlias+" ON "+creator.joinAlias+"."+userID+
+			" = "+e.TableAlias()+"."+quoteIdentifier(foreignKey))
+	}
+	return strings.Join(joins, " ")
+}
+
+// userNamingStrategy returns the naming strategy of the user table the creators are joined from.
+func (e *Entity) userNamingStrategy() NamingStrategy {
+	if e._package.config == nil || e._package.config.NamingStrategy == "" {
+		return NamingAsIs
+	}
+	return e._package.config.NamingStrategy
+}
+
+// FieldTypeName returns the name of the generated field enum type.
+func (e *Entity) FieldTypeName() string {
+	return e.name + "Field"
+}
+
+// ColumnConstantName returns the name of the generated column constant for the given property.
+func (e *Entity) ColumnConstantName(property *Property) string {
+	return e.name + "Column" + property.GetterName()
+}
+
 // PackageName returns the entity's package's name.
 func (e *Entity) PackageName() string {
 	return e._package.name
@@ -100,14 +296,30 @@ func (e *Entity) PublicNewFunctionName() string {
 // BuildFileOutput constructs the full synthesized file output for the current e.
 // nolint:funlen,gocognit,gocyclo
 func (e *Entity) BuildFileOutput() ([]byte, error) {
+	if err := e.checkComparableProperties(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
 	output := bytes.NewBufferString("// Code generated by espal-store-synthesizer. DO NOT EDIT.\n")
 	output.WriteString("package " + e.PackageName() + "\n\n")
 
 	output.WriteString("import (\n")
+	if e.comparesBytes() {
+		output.WriteString("\t" + `"bytes"` + "\n")
+	}
+	if e.HasJSONMethods() {
+		output.WriteString("\t" + `"encoding/json"` + "\n")
+	}
+	for _, path := range e.validationImports() {
+		output.WriteString("\t" + `"` + path + `"` + "\n")
+	}
 	if len(e.properties) > 0 {
 		output.WriteString("\t" + `"time"` + "\n\n")
 	}
 	output.WriteString("\t" + `"github.com/espal-digital-development/espal-core/database"` + "\n")
+	if e.HasJSONMethods() {
+		output.WriteString("\t" + `"github.com/juju/errors"` + "\n")
+	}
 	output.WriteString(")\n\n")
 
 	output.WriteString("var _ " + e.interfaceName + " = &" + e.name + "{}\n\n")
@@ -152,6 +364,17 @@ func (e *Entity) BuildFileOutput() ([]byte, error) {
 		output.WriteString(property.SetterName() + "(" + property.Name() + " " + property.Type() + ")\n")
 	}
 
+	output.WriteString("\tColumns() []string\n")
+	if e.SoftDeleteProperty() != nil {
+		output.WriteString("\tIsDeleted() bool\n")
+	}
+	e.writeCompareInterfaceMethods(output)
+	output.WriteString("\tValidate() error\n")
+	if e.TracksChanges() {
+		output.WriteString("\tChangedFields() []" + e.FieldTypeName() + "\n")
+		output.WriteString("\tResetChanges()\n")
+	}
+
 	for _, interfaceMethod := range e.extraInterfaceMethods {
 		output.WriteString("\t")
 		output.WriteString(interfaceMethod.name)
@@ -199,29 +422,103 @@ func (e *Entity) BuildFileOutput() ([]byte, error) {
 
 	output.WriteString("}")
 
+	output.WriteString("\n\n")
+	databaseProperties := e.DatabaseProperties()
+	if len(databaseProperties) > 0 {
+		output.WriteString("// Column names of the " + e.name + " table.\n")
+		output.WriteString("const (\n")
+		for _, property := range databaseProperties {
+			output.WriteString("\t" + e.ColumnConstantName(property) + ` = "` + property.ColumnName() + `"` + "\n")
+		}
+		output.WriteString(")\n\n")
+	}
+
+	// The store's queries always refer to the select columns, even when there are none
+	output.WriteString("const (\n")
+	output.WriteString("\t// " + e.name + "SelectColumns lists the " + e.name +
+		" columns in the same order as they are scanned.\n")
+	output.WriteString("\t" + e.name + "SelectColumns = `" + e.SelectColumns() + "`\n")
+	if len(e.CreatorProperties()) > 0 {
+		output.WriteString("\t// " + e.name + "SelectColumnsWithCreators lists the " + e.name +
+			" columns followed by the joined creator columns.\n")
+		output.WriteString("\t" + e.name + "SelectColumnsWithCreators = " + e.name + "SelectColumns + `, " +
+			e.CreatorSelectColumns() + "`\n")
+		output.WriteString("\t// " + e.name + "CreatorsJoin joins the user table for the creator columns of " +
+			e.name + "SelectColumnsWithCreators.\n")
+		output.WriteString("\t" + e.name + "CreatorsJoin = `" + e.CreatorsJoin() + "`\n")
+	}
+	output.WriteString(")")
+
+	if len(databaseProperties) > 0 {
+		output.WriteString("\n\n")
+		if foreignKeys := e.ForeignKeyConstraints(); len(foreignKeys) > 0 {
+			output.WriteString("// " + e.name + "ForeignKeysDDL holds the foreign key constraints of the " + e.name +
+				" table.\n")
+			output.WriteString("const " + e.name + "ForeignKeysDDL = `" + strings.Join(foreignKeys, "\n") + "`\n\n")
+		}
+
+		output.WriteString("// " + e.FieldTypeName() + " enumerates the database fields of " + e.name +
+			" in scan order.\n")
+		output.WriteString("type " + e.FieldTypeName() + " uint8\n\n")
+		output.WriteString("// " + e.FieldTypeName() + " values.\n")
+		output.WriteString("const (\n")
+		for i, property := range databaseProperties {
+			output.WriteString("\t" + e.FieldTypeName() + property.GetterName())
+			if i == 0 {
+				output.WriteString(" " + e.FieldTypeName() + " = iota")
+			}
+			output.WriteString("\n")
+		}
+		output.WriteString(")\n\n")
+
+		output.WriteString("// Column returns the field's column name.\n")
+		output.WriteString("func (f " + e.FieldTypeName() + ") Column() string {\n")
+		output.WriteString("\tswitch f {\n")
+		for _, property := range databaseProperties {
+			output.WriteString("\tcase " + e.FieldTypeName() + property.GetterName() + ":\n")
+			output.WriteString("\t\treturn " + e.ColumnConstantName(property) + "\n")
+		}
+		output.WriteString("\t}\n")
+		output.WriteString("\treturn \"\"\n")
+		output.WriteString("}\n\n")
+
+		output.WriteString("// String returns the field's column name.\n")
+		output.WriteString("func (f " + e.FieldTypeName() + ") String() string {\n")
+		output.WriteString("\treturn f.Column()\n")
+		output.WriteString("}")
+	}
+
 	// Generate the Setters and Getters
 	if e.IsPrimaryEntity() {
 		if e.tableName == "" {
 			output.WriteString("\n\n")
 			output.WriteString("// TableName returns the table name that belongs to the current model.\n")
 			output.WriteString("func (" + e.VariableName() + " *" + e.name + ") TableName() string {\n")
-			output.WriteString("\t" + `return "` + e.name + `"` + "\n")
+			output.WriteString("\t" + `return "` + e.TableName() + `"` + "\n")
 			output.WriteString("}")
 		}
 		if e.tableAlias == "" {
-			lowerCase, err := regexp.Compile(`[a-z]`)
-			if err != nil {
-				return nil, errors.Trace(err)
-			}
-			alias := strings.ToLower(lowerCase.ReplaceAllString(e.interfaceName, ""))
 			output.WriteString("\n\n")
 			output.WriteString("// TableAlias returns the unique resolved table alias for use in queries.\n")
 			output.WriteString("func (" + e.VariableName() + " *" + e.name + ") TableAlias() string {\n")
-			output.WriteString("\t" + `return "` + alias + `"` + "\n")
+			output.WriteString("\t" + `return "` + e.TableAlias() + `"` + "\n")
 			output.WriteString("}")
 		}
 	}
 
+	output.WriteString("\n\n")
+	output.WriteString("// Columns returns the database column names of the current model in scan order.\n")
+	output.WriteString("func (" + e.VariableName() + " *" + e.name + ") Columns() []string {\n")
+	output.WriteString("\treturn []string{")
+	for i, property := range databaseProperties {
+		if i > 0 {
+			output.WriteString(", ")
+		}
+		output.WriteString(e.ColumnConstantName(property))
+	}
+	output.WriteString("}\n")
+	output.WriteString("}")
+
 	output.WriteString("\n")
 
 	if len(e.properties) > 0 {
@@ -282,6 +579,14 @@ func (e *Entity) BuildFileOutput() ([]byte, error) {
 		} else {
 			output.WriteString(e.variableName + "." + property.Name() + " = " + property.Name() + "\n")
 		}
+		if e.TracksChanges() && e.isFieldProperty(property) {
+			if e.variableName == property.Name() {
+				output.WriteString("\t" + e.variableName + "Entity")
+			} else {
+				output.WriteString("\t" + e.variableName)
+			}
+			output.WriteString(".markChanged(" + e.FieldTypeName() + property.GetterName() + ")\n")
+		}
 		output.WriteString("}\n")
 
 		// Add extra methods at the end.
@@ -292,6 +597,9 @@ func (e *Entity) BuildFileOutput() ([]byte, error) {
 			output.WriteString("\treturn " + e.VariableName() + ".updatedByID != nil\n")
 			output.WriteString("}\n")
 		}
+		if property == e.SoftDeleteProperty() {
+			e.writeIsDeleted(output)
+		}
 	}
 
 	// TODO :: 7777 Automate more tests like the internal fetch() (if used already) should be tested in all ways,
@@ -302,6 +610,13 @@ func (e *Entity) BuildFileOutput() ([]byte, error) {
 	// It's only a simple helper. Each run should mark the file as generated (don't edit) and remove the
 	// previous folder and all it's contents first.
 
+	if e.TracksChanges() {
+		e.writeChangeTracking(output)
+	}
+	e.writeCompareMethods(output)
+	e.writeJSONMethods(output)
+	e.writeValidate(output)
+
 	if !e.hasPrivateNewMethod {
 		output.WriteString("\n")
 		output.WriteString("func new" + e.name + "() *" + e.name + " {\n")
@@ -325,9 +640,15 @@ func (e *Entity) BuildTestFileOutput() ([]byte, error) {
 	output.WriteString("package " + e.PackageName() + "_test\n\n")
 
 	output.WriteString("import (\n")
-	if e.ContainsBytesType() {
+	if e.DatabaseContainsBytesType() {
 		output.WriteString("\t" + `"bytes"` + "\n")
 	}
+	if e.hasJSONTest() {
+		output.WriteString("\t" + `"encoding/json"` + "\n")
+	}
+	if e.validationTestUsesStrings() {
+		output.WriteString("\t" + `"strings"` + "\n")
+	}
 	output.WriteString("\t" + `"testing"` + "\n")
 	if len(e.properties) > 0 {
 		output.WriteString("\t" + `"time"` + "\n\n")
@@ -349,11 +670,53 @@ func (e *Entity) BuildTestFileOutput() ([]byte, error) {
 	output.WriteString("\t}\n")
 	output.WriteString("}\n\n")
 
+	output.WriteString("func Test" + e.name + "Columns(t *testing.T) {\n")
+	output.WriteString("\t" + e.TestVariableName() + " := " + e.PackageName() + "." + e.PublicNewFunctionName() + "()\n")
+	output.WriteString("\t" + `if len(` + e.TestVariableName() + `.Columns()) != ` +
+		strconv.Itoa(len(e.DatabaseProperties())) + ` {` + "\n")
+	output.WriteString("\t\tt.Fatal(" + `"Columns should contain all database fields"` + ")\n")
+	output.WriteString("\t}\n")
+	output.WriteString("}\n\n")
+
+	if len(e.DatabaseProperties()) > 0 && e.IsExported() {
+		output.WriteString("func Test" + e.name + "Fields(t *testing.T) {\n")
+		output.WriteString("\t" + e.TestVariableName() + " := " + e.PackageName() + "." +
+			e.PublicNewFunctionName() + "()\n")
+		output.WriteString("\tfor i, column := range " + e.TestVariableName() + ".Columns() {\n")
+		output.WriteString("\t\tif " + e.PackageName() + "." + e.FieldTypeName() + "(i).Column() != column {\n")
+		output.WriteString("\t\t\tt.Fatalf(" + `"Field %d doesn't match column %s", i, column` + ")\n")
+		output.WriteString("\t\t}\n")
+		output.WriteString("\t}\n")
+		output.WriteString("}\n\n")
+	}
+
 	output.WriteString("func Test" + e.name + "IsUpdated(t *testing.T) {\n")
 	output.WriteString("\t" + e.TestVariableName() + " := " + e.PackageName() + "." + e.PublicNewFunctionName() + "()\n")
 	output.WriteString("\t" + e.TestVariableName() + ".IsUpdated()\n")
 	output.WriteString("}\n\n")
 
+	if e.SoftDeleteProperty() != nil {
+		output.WriteString("func Test" + e.name + "IsDeleted(t *testing.T) {\n")
+		output.WriteString("\t" + e.TestVariableName() + " := " + e.PackageName() + "." +
+			e.PublicNewFunctionName() + "()\n")
+		output.WriteString("\tif " + e.TestVariableName() + ".IsDeleted() {\n")
+		output.WriteString("\t\tt.Fatal(\"New entities shouldn't be deleted\")\n")
+		output.WriteString("\t}\n")
+		output.WriteString("\tdeletedAt := time.Now()\n")
+		output.WriteString("\t" + e.TestVariableName() + "." + e.SoftDeleteProperty().SetterName() + "(&deletedAt)\n")
+		output.WriteString("\tif !" + e.TestVariableName() + ".IsDeleted() {\n")
+		output.WriteString("\t\tt.Fatal(\"Entities with a deletedAt should be deleted\")\n")
+		output.WriteString("\t}\n")
+		output.WriteString("}\n\n")
+	}
+
+	if e.TracksChanges() {
+		e.writeChangeTrackingTest(output)
+	}
+	e.writeCompareTest(output)
+	e.writeJSONTest(output)
+	e.writeValidationTests(output)
+
 	output.WriteString("func Test" + e.name + "ID(t *testing.T) {\n")
 	output.WriteString("\t" + e.TestVariableName() + " := " + e.PackageName() + "." + e.PublicNewFunctionName() + "()\n")
 	output.WriteString("\t" + e.TestVariableName() + ".ID()\n")
@@ -373,13 +736,45 @@ func (e *Entity) addImport(path string) {
 	e.imports = append(e.imports, &Import{path: path})
 }
 
+// sampleValue returns a Go expression of a value for the property's base type that the generated
+// tests can set. It's `#FAULT` for types it doesn't know.
+func sampleValue(property *Property) string {
+	switch property.BaseType() {
+	case "float32":
+		return `float32(3.14)`
+	case "float64":
+		return `6.28`
+	case "uint8":
+		return `uint8(255)`
+	case "uint16":
+		return `uint16(65000)`
+	case "uint32":
+		return `uint32(1e6)`
+	case "uint":
+		return `uint(1e9)`
+	case "int":
+		return `int(1e8)`
+	case "string":
+		return `"testValue"`
+	case "bool":
+		return `true`
+	case "time.Time":
+		return `time.Now()`
+	case "time.Duration":
+		return `time.Second*8`
+	case "[]byte":
+		return `[]byte("testData")`
+	}
+	return "#FAULT"
+}
+
 // nolint:funlen
 func (e *Entity) processProperties(output io.StringWriter) {
 	for _, property := range e.properties {
 		if property.Name() == "id" {
 			continue
 		}
-		if strings.Contains(property.Comment(), "@synthesize-no-db-field") {
+		if !property.IsDatabaseField() {
 			continue
 		}
 
@@ -388,37 +783,8 @@ func (e *Entity) processProperties(output io.StringWriter) {
 		output.WriteString("\t" + e.TestVariableName() + " := " + e.PackageName() + "." + e.PublicNewFunctionName() + "()\n")
 		output.WriteString("\ttestValue := ")
 
-		var isBytesType bool
-
-		switch property.Type() {
-		case "float32", "*float32":
-			output.WriteString(`float32(3.14)`)
-		case "float64", "*float64":
-			output.WriteString(`6.28`)
-		case "uint8", "*uint8":
-			output.WriteString(`uint8(255)`)
-		case "uint16", "*uint16":
-			output.WriteString(`uint16(65000)`)
-		case "uint32", "*uint32":
-			output.WriteString(`uint32(1e6)`)
-		case "uint", "*uint":
-			output.WriteString(`uint(1e9)`)
-		case "int", "*int":
-			output.WriteString(`int(1e8)`)
-		case "string", "*string":
-			output.WriteString(`"testValue"`)
-		case "bool", "*bool":
-			output.WriteString(`true`)
-		case "time.Time", "*time.Time":
-			output.WriteString(`time.Now()`)
-		case "time.Duration", "*time.Duration":
-			output.WriteString(`time.Second*8`)
-		case "[]byte":
-			isBytesType = true
-			output.WriteString(`[]byte("testData")`)
-		default:
-			output.WriteString("#FAULT")
-		}
+		isBytesType := property.IsBytes()
+		output.WriteString(sampleValue(property))
 		output.WriteString("\n")
 
 		output.WriteString("\t" + e.TestVariableName() + "." + property.SetterName() + "(")
@@ -465,5 +831,12 @@ func newEntity(p *Package, bytesData []byte) *Entity {
 			"field":    true,
 			"value":    true,
 		},
+		creatorProperties: map[string]*creatorColumn{
+			"createdByFirstName": {joinAlias: "cu", foreignKey: "createdByID", property: "firstName"},
+			"createdBySurname":   {joinAlias: "cu", foreignKey: "createdByID", property: "surname"},
+			"updatedByFirstName": {joinAlias: "uu", foreignKey: "updatedByID", property: "firstName"},
+			"updatedBySurname":   {joinAlias: "uu", foreignKey: "updatedByID", property: "surname"},
+		},
+		annotations: map[string]string{},
 	}
 }
@@ -0,0 +1,29 @@
+package packages
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFieldGeneration(t *testing.T) {
+	outputs := buildTestOutputs(t, loadTestPackage(t, "order"))
+	expected := []string{
+		"type OrderLineField uint8",
+		"\tOrderLineFieldID OrderLineField = iota\n",
+		"\tcase OrderLineFieldAmount:\n\t\treturn OrderLineColumnAmount\n",
+		"\tOrderLineColumnAmount = \"amount\"\n",
+		"\tOrderLineSelectColumns = `ol.\"id\", ",
+	}
+	for _, expected := range expected {
+		if !strings.Contains(outputs["OrderLine_synthesized.go"], expected) {
+			t.Errorf("OrderLine doesn't contain %s", expected)
+		}
+	}
+	if !strings.Contains(outputs["OrderLine_synthesized_test.go"], "func TestOrderLineFields(t *testing.T) {") {
+		t.Error("OrderLine should test its fields")
+	}
+	// The test package can't refer to the fields of unexported entities
+	if strings.Contains(outputs["orderNote_synthesized_test.go"], "orderNoteField") {
+		t.Errorf("orderNote shouldn't test its fields\n%s", outputs["orderNote_synthesized_test.go"])
+	}
+}
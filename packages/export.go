@@ -0,0 +1,76 @@
+package packages
+
+import (
+	"strings"
+)
+
+// TypeNames returns a unique type name for every entity of the packages, for the schemas and
+// definitions generated for outside of Go. That's the entity's name, prefixed with its package
+// name when another package has an entity with the same name.
+func TypeNames(pkgs []*Package) map[*Entity]string {
+	counts := map[string]int{}
+	for _, pkg := range pkgs {
+		for _, entity := range pkg.AllEntities() {
+			counts[entity.name]++
+		}
+	}
+	names := map[*Entity]string{}
+	for _, pkg := range pkgs {
+		for _, entity := range pkg.AllEntities() {
+			if counts[entity.name] > 1 {
+				names[entity] = strings.Title(pkg.name) + entity.name
+				continue
+			}
+			names[entity] = entity.name
+		}
+	}
+	return names
+}
+
+// InterfaceMethods returns the methods of the package's Store interface, the hand-written ones
+// followed by the synthesized ones.
+func (s *Store) InterfaceMethods() []*Function {
+	return append(append([]*Function{}, s.methods...), s.synthesizedMethods()...)
+}
+
+// StructName returns the name of the store's struct.
+func (s *Store) StructName() string {
+	return s.structName
+}
+
+// Services returns the services injected into the store.
+func (s *Store) Services() []*Service {
+	return s.services
+}
+
+// MethodSignature returns the method's signature as written in the Store interface.
+func (s *Store) MethodSignature(method *Function) string {
+	output := &strings.Builder{}
+	s.writeInterfaceMethod(output, method)
+	return strings.TrimSpace(output.String())
+}
+
+// Name returns the name of the service's struct field.
+func (s *Service) Name() string {
+	return s.name
+}
+
+// Type returns the service's type, like `database.Database`.
+func (s *Service) Type() string {
+	return s.packageName
+}
+
+// CreatorForeignKeys returns the properties holding the IDs of the users that the creator
+// properties are joined from.
+func (e *Entity) CreatorForeignKeys() []*Property {
+	properties := []*Property{}
+	for _, property := range e.DatabaseProperties() {
+		for _, creator := range e.creatorProperties {
+			if creator.foreignKey == property.name {
+				properties = append(properties, property)
+				break
+			}
+		}
+	}
+	return properties
+}
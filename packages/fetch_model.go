@@ -1,15 +1,13 @@
 package packages
 
 // nolint:lll
-const fetchModel = `func (#STRUCT_VAR_NAME *#STRUCT_NAME) fetch(query string, withCreators bool, params ...interface{}) (result []*#ENTITY_STRUCT_NAME, ok bool, err error) {
-	rows, err := #STRUCT_VAR_NAME.selecterDatabase.Query(query, params...)
+const iterateModel = `func (#STRUCT_VAR_NAME *#STRUCT_NAME) #ITERATE_NAME(#FETCH_PARAMSquery string, withCreators bool, fn func(*#ENTITY_STRUCT_NAME) error, params ...interface{}) (err error) {
+	rows, err := #SELECTER.Query#CTX(query, params...)
 	if err == sql.ErrNoRows {
-		err = nil
-		return
+		return nil
 	}
 	if err != nil {
-		err = errors.Trace(err)
-		return
+		return errors.Trace(err)
 	}
 	defer func(dbRows database.Rows) {
 		closeErr := dbRows.Close()
@@ -19,22 +17,56 @@ const fetchModel = `func (#STRUCT_VAR_NAME *#STRUCT_NAME) fetch(query string, wi
 			err = errors.Trace(closeErr)
 		}
 	}(rows)
-	result = make([]*#ENTITY_STRUCT_NAME, 0)
 	for rows.Next() {
-		if err := rows.Err(); err != nil {
-			return nil, false, errors.Trace(err)
-		}
 		#ENTITY_STRUCT_VAR_NAME := new#ENTITY_STRUCT_NAME()
 		fields := []interface{}{#ENTITY_FIELDS}
 		if withCreators {
 			fields = append(fields, #ENTITY_CREATOR_FIELDS)
 		}
 		if err := rows.Scan(fields...); err != nil {
-			return nil, false, errors.Trace(err)
+			return errors.Trace(err)
+		}
+		if err := fn(#ENTITY_STRUCT_VAR_NAME); err != nil {
+			if errors.Cause(err) == ErrStopIteration {
+				return nil
+			}
+			return errors.Trace(err)
 		}
+	}
+	return errors.Trace(rows.Err())
+}
+`
+
+// nolint:lll
+const undeletedIterateModel = `func (#STRUCT_VAR_NAME *#STRUCT_NAME) #ITERATE_NAME(#FETCH_PARAMSquery string, withCreators bool, fn func(*#ENTITY_STRUCT_NAME) error, params ...interface{}) error {
+	return #STRUCT_VAR_NAME.#ITERATE_INCLUDING_DELETED_NAME#CTX(query, withCreators, func(#ENTITY_STRUCT_VAR_NAME *#ENTITY_STRUCT_NAME) error {
+		if #ENTITY_STRUCT_VAR_NAME.#DELETED_AT != nil {
+			return nil
+		}
+		return fn(#ENTITY_STRUCT_VAR_NAME)
+	}, params...)
+}
+`
+
+// nolint:lll
+const fetchModel = `func (#STRUCT_VAR_NAME *#STRUCT_NAME) #FETCH_NAME(#FETCH_PARAMSquery string, withCreators bool, params ...interface{}) ([]*#ENTITY_STRUCT_NAME, bool, error) {
+	result := make([]*#ENTITY_STRUCT_NAME, 0)
+	err := #STRUCT_VAR_NAME.#ITERATE_NAME#CTX(query, withCreators, func(#ENTITY_STRUCT_VAR_NAME *#ENTITY_STRUCT_NAME) error {
 		result = append(result, #ENTITY_STRUCT_VAR_NAME)
+		return nil
+	}, params...)
+	if err != nil {
+		return nil, false, errors.Trace(err)
+	}
+	return result, len(result) > 0, nil
+}
+`
+
+const countModel = `func (#STRUCT_VAR_NAME *#STRUCT_NAME) #COUNT_NAME(#FETCH_PARAMSquery string, params ...interface{}) (uint, error) {
+	var count uint
+	if err := #SELECTER.QueryRow#CTX(query, params...).Scan(&count); err != nil {
+		return 0, errors.Trace(err)
 	}
-	ok = len(result) > 0
-	return
+	return count, nil
 }
 `
@@ -0,0 +1,310 @@
+package packages
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	// FixturesPackageName is the name of the generated fixtures package inside every store package.
+	FixturesPackageName = "testfixtures"
+	// defaultFixtureMin and defaultFixtureMax bound the fake numbers without validation rules.
+	defaultFixtureMin = 1
+	defaultFixtureMax = 100
+	// defaultFixtureBytes is the length of fake byte-arrays without validation rules.
+	defaultFixtureBytes = 8
+	// maxExactInteger is the highest whole number a float64 holds exactly.
+	maxExactInteger = 1 << 53
+)
+
+// nolint:lll
+const fixturesModel = `// DefaultSeed is the seed the fake values start from and that Seed can reset to.
+const DefaultSeed int64 = 1
+
+var (
+	randomMutex sync.Mutex
+	random      = rand.New(rand.NewSource(DefaultSeed)) // nolint:gosec
+	baseTime    = time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// Seed restarts the fake values from the given seed, so the following fixtures are the same every run.
+func Seed(seed int64) {
+	randomMutex.Lock()
+	defer randomMutex.Unlock()
+	random = rand.New(rand.NewSource(seed)) // nolint:gosec
+}
+
+func randomInt(min int64, max int64) int64 {
+	randomMutex.Lock()
+	defer randomMutex.Unlock()
+	return min + random.Int63n(max-min+1)
+}
+
+func randomFloat(min float64, max float64) float64 {
+	randomMutex.Lock()
+	defer randomMutex.Unlock()
+	return min + random.Float64()*(max-min)
+}
+
+func randomBool() bool {
+	return randomInt(0, 1) == 1
+}
+
+func randomString(prefix string) string {
+	return prefix + "-" + strconv.FormatInt(randomInt(0, 999999), 10)
+}
+
+func randomEmail() string {
+	return "user" + strconv.FormatInt(randomInt(0, 999999), 10) + "@example.com"
+}
+
+func randomUUID() string {
+	return fmt.Sprintf("%08x-%04x-4%03x-8%03x-%012x", randomInt(0, 1<<32-1), randomInt(0, 1<<16-1),
+		randomInt(0, 1<<12-1), randomInt(0, 1<<12-1), randomInt(0, 1<<48-1))
+}
+
+func randomTime() time.Time {
+	return baseTime.Add(time.Duration(randomInt(0, 365*24*60*60)) * time.Second)
+}
+
+func randomBytes(length int64) []byte {
+	data := make([]byte, length)
+	for i := range data {
+		data[i] = byte(randomInt(0, 255))
+	}
+	return data
+}
+
+// fitLength pads or cuts the value to the length bounds, where a negative max means no bound.
+func fitLength(value string, min int, max int) string {
+	for len(value) < min {
+		value += "x"
+	}
+	if max >= 0 && len(value) > max {
+		value = value[:max]
+	}
+	return value
+}
+`
+
+// FixturePath returns the directory of the package's generated fixtures package.
+func (p *Package) FixturePath() string {
+	return p.path + "/" + FixturesPackageName
+}
+
+// fixtureEntities returns the entities fixtures can be generated for, which are the exported ones.
+func (p *Package) fixtureEntities() []*Entity {
+	entities := []*Entity{}
+	for _, entity := range p.AllEntities() {
+		if !entity.IsExported() {
+			continue
+		}
+		entities = append(entities, entity)
+	}
+	return entities
+}
+
+// validationBounds returns the bounds of the property's min and max rules and which of them it has.
+func (p *Property) validationBounds() (min float64, max float64, hasMin bool, hasMax bool) {
+	for _, rule := range p.validationRules {
+		value, _ := strconv.ParseFloat(rule.argument, 64)
+		switch rule.name {
+		case ValidationMin:
+			min, hasMin = value, true
+		case ValidationMax:
+			max, hasMax = value, true
+		}
+	}
+	return min, max, hasMin, hasMax
+}
+
+// hasValidationRule returns if the property has the given validation rule.
+func (p *Property) hasValidationRule(name string) bool {
+	for _, rule := range p.validationRules {
+		if rule.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// fixtureValue returns the Go expression of a fake value for the property that passes its
+// validation rules. It's empty for types it doesn't know.
+// nolint:gocyclo,funlen
+func (p *Property) fixtureValue() string {
+	min, max, hasMin, hasMax := p.validationBounds()
+	format := func(value float64) string {
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	baseType := p.BaseType()
+	switch {
+	case baseType == "string":
+		var value string
+		switch {
+		case p.hasValidationRule(ValidationEmail) || strings.Contains(strings.ToLower(p.name), "email"):
+			value = "randomEmail()"
+		case p.SQLType() == "UUID":
+			value = "randomUUID()"
+		default:
+			value = `randomString("` + strings.TrimPrefix(p.name, "_") + `")`
+		}
+		if !hasMin && !hasMax {
+			return value
+		}
+		maxLength := "-1"
+		if hasMax {
+			maxLength = format(max)
+		}
+		return "fitLength(" + value + ", " + format(min) + ", " + maxLength + ")"
+	case baseType == "bool":
+		if p.hasValidationRule(ValidationRequired) {
+			return "true"
+		}
+		return "randomBool()"
+	case baseType == "time.Time":
+		return "randomTime()"
+	case baseType == "[]byte":
+		length := float64(defaultFixtureBytes)
+		if hasMax && max < length {
+			length = max
+		}
+		if hasMin && min > length {
+			length = min
+		}
+		return "randomBytes(" + format(length) + ")"
+	case baseType == "time.Duration" && !hasMin && !hasMax:
+		return "time.Duration(randomInt(1, 3600)) * time.Second"
+	}
+	bounds, ok := numericBounds[baseType]
+	if !ok {
+		return ""
+	}
+	if !hasMin {
+		min = math.Max(defaultFixtureMin, bounds[0])
+		if hasMax && min > max {
+			min = max
+		}
+	}
+	if !hasMax {
+		max = math.Min(min+defaultFixtureMax, bounds[1])
+	}
+	if strings.HasPrefix(baseType, "float") {
+		return baseType + "(randomFloat(" + format(min) + ", " + format(max) + "))"
+	}
+	// Keep the bounds within what randomInt's int64 and the float parsing can hold exactly
+	min, max = math.Max(min, -maxExactInteger), math.Min(max, maxExactInteger)
+	return baseType + "(randomInt(" + format(min) + ", " + format(max) + "))"
+}
+
+// hasVaryingFixture returns if one of the compared properties gets a fake value that's practically
+// never the same in following fixtures.
+func (e *Entity) hasVaryingFixture() bool {
+	for _, property := range e.comparableProperties() {
+		if value := property.fixtureValue(); strings.Contains(value, "random") && value != "randomBool()" {
+			return true
+		}
+	}
+	return false
+}
+
+// OptionTypeName returns the name of the fixture option type of the entity.
+func (e *Entity) OptionTypeName() string {
+	return e.name + "Option"
+}
+
+// FixtureFunctionName returns the name of the function that creates a fixture of the entity.
+func (e *Entity) FixtureFunctionName() string {
+	return "New" + e.name + "Fixture"
+}
+
+// writeFixture writes the entity's option type and fixture function.
+func (e *Entity) writeFixture(output io.StringWriter) {
+	v := e.VariableName()
+	qualifiedName := e.PackageName() + "." + e.name
+	output.WriteString("\n")
+	output.WriteString("// " + e.OptionTypeName() + " overrides values of a " + e.name + " fixture.\n")
+	output.WriteString("type " + e.OptionTypeName() + " func(" + v + " *" + qualifiedName + ")\n")
+	output.WriteString("\n")
+	output.WriteString("// " + e.FixtureFunctionName() + " returns a " + e.name +
+		" with fake values for all properties but the id,\n")
+	output.WriteString("// relations and deletion, which the options can then override.\n")
+	output.WriteString("func " + e.FixtureFunctionName() + "(options ..." + e.OptionTypeName() + ") *" +
+		qualifiedName + " {\n")
+	output.WriteString("\t" + v + " := " + e.PackageName() + "." + e.PublicNewFunctionName() + "().(*" +
+		qualifiedName + ")\n")
+	for _, property := range e.properties {
+		value := property.fixtureValue()
+		if property.name == "id" || property == e.SoftDeleteProperty() || value == "" {
+			continue
+		}
+		if property.IsPointer() {
+			output.WriteString("\t{\n")
+			output.WriteString("\t\tvalue := " + value + "\n")
+			output.WriteString("\t\t" + v + "." + property.SetterName() + "(&value)\n")
+			output.WriteString("\t}\n")
+			continue
+		}
+		output.WriteString("\t" + v + "." + property.SetterName() + "(" + value + ")\n")
+	}
+	if e.TracksChanges() {
+		output.WriteString("\t" + v + ".ResetChanges()\n")
+	}
+	output.WriteString("\tfor _, option := range options {\n")
+	output.WriteString("\t\toption(" + v + ")\n")
+	output.WriteString("\t}\n")
+	output.WriteString("\treturn " + v + "\n")
+	output.WriteString("}\n")
+}
+
+// BuildFixtureFileOutput constructs the synthesized file of the package's fixtures package.
+func (p *Package) BuildFixtureFileOutput() ([]byte, error) {
+	output := bytes.NewBufferString("// Code generated by espal-store-synthesizer. DO NOT EDIT.\n")
+	output.WriteString("package " + FixturesPackageName + "\n\n")
+	output.WriteString("import (\n")
+	output.WriteString("\t" + `"fmt"` + "\n")
+	output.WriteString("\t" + `"math/rand"` + "\n")
+	output.WriteString("\t" + `"strconv"` + "\n")
+	output.WriteString("\t" + `"sync"` + "\n")
+	output.WriteString("\t" + `"time"` + "\n\n")
+	output.WriteString("\t" + `"` + p.importPath + `"` + "\n")
+	output.WriteString(")\n\n")
+	output.WriteString(fixturesModel)
+	for _, entity := range p.fixtureEntities() {
+		entity.writeFixture(output)
+	}
+	return output.Bytes(), nil
+}
+
+// BuildFixtureTestFileOutput constructs the tests that the fixtures are deterministic and valid.
+func (p *Package) BuildFixtureTestFileOutput() ([]byte, error) {
+	output := bytes.NewBufferString("// Code generated by espal-store-synthesizer. DO NOT EDIT.\n")
+	output.WriteString("package " + FixturesPackageName + "_test\n\n")
+	output.WriteString("import (\n")
+	output.WriteString("\t" + `"testing"` + "\n\n")
+	output.WriteString("\t" + `"` + p.importPath + "/" + FixturesPackageName + `"` + "\n")
+	output.WriteString(")\n")
+	for _, entity := range p.fixtureEntities() {
+		function := FixturesPackageName + "." + entity.FixtureFunctionName()
+		output.WriteString("\n")
+		output.WriteString("func Test" + entity.FixtureFunctionName() + "(t *testing.T) {\n")
+		output.WriteString("\t" + FixturesPackageName + ".Seed(" + FixturesPackageName + ".DefaultSeed)\n")
+		output.WriteString("\tfirst := " + function + "()\n")
+		output.WriteString("\t" + FixturesPackageName + ".Seed(" + FixturesPackageName + ".DefaultSeed)\n")
+		output.WriteString("\tif !first.Equal(" + function + "()) {\n")
+		output.WriteString("\t\tt.Fatal(\"Fixtures from the same seed should be equal\")\n")
+		output.WriteString("\t}\n")
+		output.WriteString("\tif err := first.Validate(); err != nil {\n")
+		output.WriteString("\t\tt.Fatalf(\"Fixtures should be valid: %v\", err)\n")
+		output.WriteString("\t}\n")
+		if entity.hasVaryingFixture() {
+			output.WriteString("\tif first.Equal(" + function + "()) {\n")
+			output.WriteString("\t\tt.Fatal(\"Following fixtures should differ\")\n")
+			output.WriteString("\t}\n")
+		}
+		output.WriteString("}\n")
+	}
+	return output.Bytes(), nil
+}
@@ -28,3 +28,38 @@ func (f *Function) ContainsNamedReturnValue() bool {
 	}
 	return false
 }
+
+// Name returns the parameter's name.
+func (p *FunctionParameter) Name() string {
+	return p.name
+}
+
+// Type returns the parameter's type as written in Go.
+func (p *FunctionParameter) Type() string {
+	return p._type
+}
+
+// Name returns the return value's name, which is only set for named return values.
+func (r *FunctionReturnValue) Name() string {
+	return r.name
+}
+
+// Type returns the return value's type as written in Go.
+func (r *FunctionReturnValue) Type() string {
+	return r._type
+}
+
+// Name returns the function's name.
+func (f *Function) Name() string {
+	return f.name
+}
+
+// Parameters returns the function's parameters.
+func (f *Function) Parameters() []*FunctionParameter {
+	return f.parameters
+}
+
+// ReturnValues returns the function's return values.
+func (f *Function) ReturnValues() []*FunctionReturnValue {
+	return f.returnValues
+}
@@ -0,0 +1,178 @@
+package packages
+
+import (
+	"io"
+	"strings"
+)
+
+// auditProperties are the properties that are read-only when unmarshalling JSON, next to the creator properties.
+var auditProperties = map[string]bool{
+	"createdByID": true,
+	"updatedByID": true,
+	"createdAt":   true,
+	"updatedAt":   true,
+}
+
+// jsonOptions returns the options of the property's `@synthesize-json name,readonly` comment.
+func (p *Property) jsonOptions() []string {
+	options, _ := p.Annotation("json")
+	return strings.Split(options, ",")
+}
+
+// JSONName returns the property's key in the entity's JSON. It's the property name, unless renamed
+// with a `@synthesize-json name` comment. It's empty when omitted with `@synthesize-json -`.
+func (p *Property) JSONName() string {
+	name := p.jsonOptions()[0]
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return strings.TrimPrefix(p.name, "_")
+	}
+	return name
+}
+
+// JSONProperties returns the properties that are part of the entity's JSON.
+func (e *Entity) JSONProperties() []*Property {
+	properties := make([]*Property, 0, len(e.properties))
+	for _, property := range e.properties {
+		if property.JSONName() != "" {
+			properties = append(properties, property)
+		}
+	}
+	return properties
+}
+
+// IsJSONReadOnly returns if the property is marshalled but ignored when unmarshalling. That's
+// the case for the creator and audit properties and those with a `@synthesize-json name,readonly` comment.
+func (e *Entity) IsJSONReadOnly(property *Property) bool {
+	if _, ok := e.creatorProperties[property.name]; ok || auditProperties[property.name] {
+		return true
+	}
+	for _, option := range property.jsonOptions()[1:] {
+		if option == "readonly" {
+			return true
+		}
+	}
+	return false
+}
+
+// HasJSONMethods returns if the entity gets MarshalJSON and UnmarshalJSON methods, which is when
+// any of its properties is part of its JSON.
+func (e *Entity) HasJSONMethods() bool {
+	return len(e.JSONProperties()) > 0
+}
+
+// writeJSONMethods writes the entity's MarshalJSON and UnmarshalJSON methods.
+func (e *Entity) writeJSONMethods(output io.StringWriter) {
+	if !e.HasJSONMethods() {
+		return
+	}
+	v := e.VariableName()
+	properties := e.JSONProperties()
+
+	output.WriteString("\n")
+	output.WriteString("// MarshalJSON encodes the entity as a JSON object.\n")
+	output.WriteString("func (" + v + " *" + e.name + ") MarshalJSON() ([]byte, error) {\n")
+	output.WriteString("\treturn json.Marshal(struct {\n")
+	for _, property := range properties {
+		output.WriteString("\t\t" + property.GetterName() + " " + property._type + " `json:\"" +
+			property.JSONName() + "\"`\n")
+	}
+	output.WriteString("\t}{\n")
+	for _, property := range properties {
+		output.WriteString("\t\t" + property.GetterName() + ": " + v + "." + property.name + ",\n")
+	}
+	output.WriteString("\t})\n")
+	output.WriteString("}\n")
+
+	output.WriteString("\n")
+	output.WriteString("// UnmarshalJSON sets the properties present in the JSON object through their setters.\n")
+	output.WriteString("// The creator and audit properties are read-only and are left untouched.\n")
+	output.WriteString("func (" + v + " *" + e.name + ") UnmarshalJSON(data []byte) error {\n")
+	output.WriteString("\tfields := map[string]json.RawMessage{}\n")
+	output.WriteString("\tif err := json.Unmarshal(data, &fields); err != nil {\n")
+	output.WriteString("\t\treturn errors.Trace(err)\n")
+	output.WriteString("\t}\n")
+	for _, property := range properties {
+		if e.IsJSONReadOnly(property) {
+			continue
+		}
+		output.WriteString("\tif raw, ok := fields[\"" + property.JSONName() + "\"]; ok {\n")
+		output.WriteString("\t\tvar value " + property._type + "\n")
+		output.WriteString("\t\tif err := json.Unmarshal(raw, &value); err != nil {\n")
+		output.WriteString("\t\t\treturn errors.Annotate(err, \"" + property.JSONName() + "\")\n")
+		output.WriteString("\t\t}\n")
+		if property.name == "id" {
+			output.WriteString("\t\t" + v + ".id = value\n")
+		} else {
+			output.WriteString("\t\t" + v + "." + property.SetterName() + "(value)\n")
+		}
+		output.WriteString("\t}\n")
+	}
+	output.WriteString("\treturn nil\n")
+	output.WriteString("}\n")
+}
+
+// hasJSONTest returns if the JSON round-trip test is written for the entity. The test compares
+// the unmarshalled entity as its struct, so it's left out for unexported entities.
+func (e *Entity) hasJSONTest() bool {
+	return e.HasJSONMethods() && e.IsExported()
+}
+
+// writeJSONTest writes the test that a fully set entity survives a JSON round-trip, apart from
+// its read-only and omitted properties.
+func (e *Entity) writeJSONTest(output io.StringWriter) {
+	if !e.hasJSONTest() {
+		return
+	}
+	v := e.TestVariableName()
+	output.WriteString("func Test" + e.name + "JSON(t *testing.T) {\n")
+	output.WriteString("\t" + v + " := " + e.PackageName() + "." + e.PublicNewFunctionName() + "()\n")
+	for _, property := range e.properties {
+		value := sampleValue(property)
+		if property.name == "id" || value == "#FAULT" {
+			continue
+		}
+		if property.IsPointer() {
+			output.WriteString("\t{\n")
+			output.WriteString("\t\tvalue := " + value + "\n")
+			output.WriteString("\t\t" + v + "." + property.SetterName() + "(&value)\n")
+			output.WriteString("\t}\n")
+			continue
+		}
+		output.WriteString("\t" + v + "." + property.SetterName() + "(" + value + ")\n")
+	}
+	output.WriteString("\tdata, err := json.Marshal(" + v + ")\n")
+	output.WriteString("\tif err != nil {\n")
+	output.WriteString("\t\tt.Fatal(err)\n")
+	output.WriteString("\t}\n")
+	output.WriteString("\tunmarshalled := " + e.PackageName() + "." + e.PublicNewFunctionName() + "()\n")
+	output.WriteString("\tif err := json.Unmarshal(data, unmarshalled); err != nil {\n")
+	output.WriteString("\t\tt.Fatal(err)\n")
+	output.WriteString("\t}\n")
+	output.WriteString("\tskipped := map[string]bool{")
+	var firstHad bool
+	for _, property := range e.comparableProperties() {
+		if property.name == "id" || sampleValue(property) == "#FAULT" ||
+			(property.JSONName() != "" && !e.IsJSONReadOnly(property)) {
+			continue
+		}
+		if firstHad {
+			output.WriteString(", ")
+		}
+		firstHad = true
+		output.WriteString("\"" + property.name + "\": true")
+	}
+	output.WriteString("}\n")
+	output.WriteString("\tdiff := " + v + ".Diff(unmarshalled.(*" + e.PackageName() + "." + e.name + "))\n")
+	output.WriteString("\tfor _, change := range diff {\n")
+	output.WriteString("\t\tif !skipped[change.Field] {\n")
+	output.WriteString("\t\t\tt.Fatalf(\"%s didn't survive the round-trip\", change.Field)\n")
+	output.WriteString("\t\t}\n")
+	output.WriteString("\t}\n")
+	output.WriteString("\tif len(diff) != len(skipped) {\n")
+	output.WriteString("\t\tt.Fatalf(\"Read-only and omitted fields should be skipped, got %v\", diff)\n")
+	output.WriteString("\t}\n")
+	output.WriteString("}\n\n")
+}
@@ -0,0 +1,63 @@
+package packages
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestJSONGeneration(t *testing.T) {
+	tests := []struct {
+		pkg      string
+		file     string
+		expected []string
+		missing  []string
+	}{
+		{"user", "User_synthesized.go", []string{
+			`"encoding/json"`,
+			`"github.com/juju/errors"`,
+			"\t\tEmail string `json:\"emailAddress\"`\n",
+			"\tif err := json.Unmarshal(data, &fields); err != nil {\n\t\treturn errors.Trace(err)\n\t}\n",
+			"\tif raw, ok := fields[\"emailAddress\"]; ok {\n" +
+				"\t\tvar value string\n" +
+				"\t\tif err := json.Unmarshal(raw, &value); err != nil {\n" +
+				"\t\t\treturn errors.Annotate(err, \"emailAddress\")\n" +
+				"\t\t}\n" +
+				"\t\tu.SetEmail(value)\n",
+		}, []string{
+			`"fmt"`,
+			"IsDirty bool",
+			`fields["createdAt"]`,
+		}},
+		{"user", "User_synthesized_test.go", []string{
+			`"encoding/json"`,
+			"func TestUserJSON(t *testing.T) {",
+			"\tdiff := u.Diff(unmarshalled.(*user.User))\n",
+		}, nil},
+		{"order", "orderNote_synthesized.go", nil, []string{
+			`"encoding/json"`,
+			`"github.com/juju/errors"`,
+			"MarshalJSON",
+		}},
+		{"order", "orderNote_synthesized_test.go", nil, []string{
+			`"encoding/json"`,
+			"func TestorderNoteJSON(",
+		}},
+	}
+	outputs := map[string]map[string]string{}
+	for _, test := range tests {
+		if _, ok := outputs[test.pkg]; !ok {
+			outputs[test.pkg] = buildTestOutputs(t, loadTestPackage(t, test.pkg))
+		}
+		output := outputs[test.pkg][test.file]
+		for _, expected := range test.expected {
+			if !strings.Contains(output, expected) {
+				t.Errorf("%s doesn't contain %s", test.file, expected)
+			}
+		}
+		for _, missing := range test.missing {
+			if strings.Contains(output, missing) {
+				t.Errorf("%s shouldn't contain %s", test.file, missing)
+			}
+		}
+	}
+}
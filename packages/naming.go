@@ -0,0 +1,87 @@
+package packages
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/juju/errors"
+)
+
+// NamingStrategy defines how Go identifiers are converted to database table and column names.
+type NamingStrategy string
+
+const (
+	// NamingAsIs uses the Go identifiers verbatim.
+	NamingAsIs NamingStrategy = "as-is"
+	// NamingSnakeCase converts the Go identifiers to snake_case.
+	NamingSnakeCase NamingStrategy = "snake"
+	// NamingPluralSnakeCase converts the Go identifiers to snake_case and pluralizes the table names.
+	NamingPluralSnakeCase NamingStrategy = "plural-snake"
+)
+
+// ParseNamingStrategy returns the NamingStrategy for the given name.
+func ParseNamingStrategy(name string) (NamingStrategy, error) {
+	switch NamingStrategy(name) {
+	case NamingAsIs, NamingSnakeCase, NamingPluralSnakeCase:
+		return NamingStrategy(name), nil
+	}
+	return "", errors.Errorf("unknown naming strategy `%s`", name)
+}
+
+// TableName converts the given entity name to a table name.
+func (n NamingStrategy) TableName(name string) string {
+	switch n {
+	case NamingSnakeCase:
+		return toSnakeCase(name)
+	case NamingPluralSnakeCase:
+		return pluralize(toSnakeCase(name))
+	}
+	return name
+}
+
+// ColumnName converts the given property name to a column name.
+func (n NamingStrategy) ColumnName(name string) string {
+	// Properties like `_type` only have the underscore to dodge Go's keywords
+	name = strings.TrimLeft(name, "_")
+	if n == NamingSnakeCase || n == NamingPluralSnakeCase {
+		return toSnakeCase(name)
+	}
+	return name
+}
+
+// quoteIdentifier quotes the given table or column name for use in queries.
+func quoteIdentifier(name string) string {
+	return `"` + name + `"`
+}
+
+// toSnakeCase converts camelCase and PascalCase to snake_case while keeping
+// initialisms together (`createdByID` becomes `created_by_id`).
+func toSnakeCase(s string) string {
+	runes := []rune(s)
+	output := strings.Builder{}
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			previous := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) || (unicode.IsUpper(previous) && nextIsLower) {
+				output.WriteRune('_')
+			}
+		}
+		output.WriteRune(unicode.ToLower(r))
+	}
+	return output.String()
+}
+
+// pluralize applies the basic English pluralization rules to the given word.
+func pluralize(s string) string {
+	switch {
+	case s == "":
+		return s
+	case strings.HasSuffix(s, "y") && len(s) > 1 && !strings.ContainsAny(s[len(s)-2:len(s)-1], "aeiou"):
+		return s[:len(s)-1] + "ies"
+	case strings.HasSuffix(s, "s"), strings.HasSuffix(s, "x"), strings.HasSuffix(s, "z"),
+		strings.HasSuffix(s, "ch"), strings.HasSuffix(s, "sh"):
+		return s + "es"
+	}
+	return s + "s"
+}
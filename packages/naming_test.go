@@ -0,0 +1,112 @@
+package packages
+
+import (
+	"testing"
+)
+
+func TestToSnakeCase(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"", ""},
+		{"id", "id"},
+		{"firstName", "first_name"},
+		{"UserTranslation", "user_translation"},
+		{"createdByID", "created_by_id"},
+		{"HTTPServer", "http_server"},
+		{"address2Line", "address2_line"},
+		{"ID", "id"},
+	}
+	for _, test := range tests {
+		if actual := toSnakeCase(test.input); actual != test.expected {
+			t.Errorf("toSnakeCase(%q) = %q, expected %q", test.input, actual, test.expected)
+		}
+	}
+}
+
+func TestPluralize(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"", ""},
+		{"user", "users"},
+		{"category", "categories"},
+		{"day", "days"},
+		{"address", "addresses"},
+		{"box", "boxes"},
+		{"quiz", "quizes"},
+		{"batch", "batches"},
+		{"wish", "wishes"},
+		{"y", "ys"},
+	}
+	for _, test := range tests {
+		if actual := pluralize(test.input); actual != test.expected {
+			t.Errorf("pluralize(%q) = %q, expected %q", test.input, actual, test.expected)
+		}
+	}
+}
+
+func TestNamingStrategy(t *testing.T) {
+	tests := []struct {
+		strategy NamingStrategy
+		entity   string
+		table    string
+		property string
+		column   string
+	}{
+		{NamingAsIs, "UserAddress", "UserAddress", "_type", "type"},
+		{NamingAsIs, "UserAddress", "UserAddress", "createdByID", "createdByID"},
+		{NamingSnakeCase, "UserAddress", "user_address", "createdByID", "created_by_id"},
+		{NamingPluralSnakeCase, "UserAddress", "user_addresses", "createdByID", "created_by_id"},
+		{NamingPluralSnakeCase, "Category", "categories", "_type", "type"},
+	}
+	for _, test := range tests {
+		if actual := test.strategy.TableName(test.entity); actual != test.table {
+			t.Errorf("%s TableName(%q) = %q, expected %q", test.strategy, test.entity, actual, test.table)
+		}
+		if actual := test.strategy.ColumnName(test.property); actual != test.column {
+			t.Errorf("%s ColumnName(%q) = %q, expected %q", test.strategy, test.property, actual, test.column)
+		}
+	}
+}
+
+func TestParseNamingStrategy(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected NamingStrategy
+		fails    bool
+	}{
+		{"as-is", NamingAsIs, false},
+		{"snake", NamingSnakeCase, false},
+		{"plural-snake", NamingPluralSnakeCase, false},
+		{"camel", "", true},
+		{"", "", true},
+	}
+	for _, test := range tests {
+		actual, err := ParseNamingStrategy(test.name)
+		if (err != nil) != test.fails {
+			t.Errorf("ParseNamingStrategy(%q) error = %v, expected failure %t", test.name, err, test.fails)
+		}
+		if actual != test.expected {
+			t.Errorf("ParseNamingStrategy(%q) = %q, expected %q", test.name, actual, test.expected)
+		}
+	}
+}
+
+func TestQuoteIdentifier(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"user", `"user"`},
+		{"created_by_id", `"created_by_id"`},
+		{"UserAddress", `"UserAddress"`},
+	}
+	for _, test := range tests {
+		if actual := quoteIdentifier(test.input); actual != test.expected {
+			t.Errorf("quoteIdentifier(%q) = %s, expected %s", test.input, actual, test.expected)
+		}
+	}
+}
@@ -17,10 +17,31 @@ const (
 	parametersSplitParts        = 2
 	returnValuesSplitParts      = 2
 	synthesizeOccurrencesAmount = 2
+
+	// DefaultMaxParams is the maximum amount of bind parameters a postgres statement can take.
+	DefaultMaxParams = 65535
 )
 
+// Config holds the synthesis options that apply to all packages.
+type Config struct {
+	// NamingStrategy is the default strategy for table and column names.
+	NamingStrategy NamingStrategy
+	// MaxParams limits the bind parameters per statement, which decides the chunk sizes
+	// of the multi-row statements. It defaults to DefaultMaxParams.
+	MaxParams int
+}
+
+// maxParams returns the configured maximum amount of bind parameters per statement.
+func (p *Package) maxParams() int {
+	if p.config == nil || p.config.MaxParams <= 0 {
+		return DefaultMaxParams
+	}
+	return p.config.MaxParams
+}
+
 // Package wrapping store structure.
 type Package struct {
+	config     *Config
 	mainEntity *Entity
 	entities   []*Entity
 	store      *Store
@@ -36,11 +57,13 @@ type Package struct {
 	reInterfaceMethodLinesCheck *regexp.Regexp
 	reTableNameCheck            *regexp.Regexp
 	reTableAliasCheck           *regexp.Regexp
+	reEntityAnnotations         *regexp.Regexp
 
 	rePublicMethodsCheck     *regexp.Regexp
 	reImportBlockCheck       *regexp.Regexp
 	reImportStatementsCheck  *regexp.Regexp
 	reStoreStructBlockCheck  *regexp.Regexp
+	reStructFieldsCheck      *regexp.Regexp
 	reServicesCheck          *regexp.Regexp
 	rePackagesInMethodsCheck *regexp.Regexp
 }
@@ -50,6 +73,16 @@ func (p *Package) Path() string {
 	return p.path
 }
 
+// ImportPath returns the package's Go import path.
+func (p *Package) ImportPath() string {
+	return p.importPath
+}
+
+// Name returns the package's name.
+func (p *Package) Name() string {
+	return p.name
+}
+
 // Store returns the package's store object.
 func (p *Package) Store() *Store {
 	return p.store
@@ -65,6 +98,25 @@ func (p *Package) Entities() []*Entity {
 	return p.entities
 }
 
+// AllEntities returns the package's main entity followed by its other entities.
+func (p *Package) AllEntities() []*Entity {
+	return append([]*Entity{p.mainEntity}, p.entities...)
+}
+
+// RemoveSynthesizedFiles wipes the package's existing synthesized files in case a new structure is chosen.
+func (p *Package) RemoveSynthesizedFiles() error {
+	entries, err := zglob.Glob(p.path + "/*_synthesized*")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, entry := range entries {
+		if err := os.Remove(entry); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
 // BuildMetaData collects all the package information from the path and builds and fills the necessary objects.
 // nolint:funlen,gocognit,gocyclo
 func (p *Package) BuildMetaData(path string) error {
@@ -91,15 +143,6 @@ func (p *Package) BuildMetaData(path string) error {
 	}
 	p.importPath = importPath.String()
 
-	// Wipe any existing synthetized files in case a new structure is chosen
-	for _, entry := range entries {
-		if strings.Contains(entry, "_synthesized") {
-			if err := os.Remove(entry); err != nil {
-				return errors.Trace(err)
-			}
-		}
-	}
-
 	// Store and primary entity first
 	var hasStoreFile bool
 	var hasEntityFile bool
@@ -249,7 +292,7 @@ func (p *Package) BuildMetaData(path string) error {
 		}
 	}
 
-	return nil
+	return errors.Trace(p.resolveRelations())
 }
 
 // nolint:funlen,gocognit
@@ -265,6 +308,10 @@ func (p *Package) storeFromFile(b []byte) error {
 		return errors.Errorf("not one struct found in `%s`", p.name)
 	}
 	p.store.structName = string(structBlockMatches[0][1])
+	p.store.contextAware = bytes.Contains(b, []byte("// @synthesize-context\n"))
+	for _, field := range p.reStructFieldsCheck.FindAllSubmatch(structBlockMatches[0][2], -1) {
+		p.store.fields[string(field[1])] = true
+	}
 
 	alreadyImported := make(map[string]bool)
 	importBlock := p.reImportBlockCheck.FindAllSubmatch(b, 1)
@@ -422,6 +469,11 @@ func (p *Package) entityFromFile(b []byte) (*Entity, error) {
 	entity.name = string(structBlockMatches[1])
 	entity.interfaceName = strings.Title(entity.name) + "Entity"
 
+	annotations := p.reEntityAnnotations.FindAllSubmatch(b, -1)
+	for _, annotation := range annotations {
+		entity.annotations[string(annotation[1])] = string(bytes.TrimSpace(annotation[2]))
+	}
+
 	structLines := p.reStructBlockCheck.Find(b)
 	lines := p.rePropertyLinesCheck.FindAllSubmatch(structLines, -1)
 	for _, line := range lines {
@@ -442,6 +494,28 @@ func (p *Package) entityFromFile(b []byte) (*Entity, error) {
 		entity.properties = append(entity.properties, property)
 	}
 
+	if err := entity.extractChangedFieldsProperty(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := entity.relationsFromProperties(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := entity.validationRulesFromProperties(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	namingStrategy, err := entity.NamingStrategy()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, property := range entity.properties {
+		if columnName, ok := property.Annotation("column"); ok && columnName != "" {
+			property.columnName = columnName
+		} else {
+			property.columnName = namingStrategy.ColumnName(property.name)
+		}
+	}
+
 	if entity.ContainsBytesType() {
 		entity.addImport("bytes")
 	}
@@ -490,16 +564,14 @@ func (p *Package) entityFromFile(b []byte) (*Entity, error) {
 
 	// Register the table name/alias and if it's already uses
 	// the needed methods
-	if entity.IsPrimaryEntity() {
-		tableNameCheck := p.reTableNameCheck.FindSubmatch(b)
-		if len(tableNameCheck) > 0 {
-			entity.tableName = string(tableNameCheck[1])
-		}
+	tableNameCheck := p.reTableNameCheck.FindSubmatch(b)
+	if len(tableNameCheck) > 0 {
+		entity.tableName = string(tableNameCheck[1])
+	}
 
-		tableAliasCheck := p.reTableAliasCheck.FindSubmatch(b)
-		if len(tableAliasCheck) > 0 {
-			entity.tableAlias = string(tableAliasCheck[1])
-		}
+	tableAliasCheck := p.reTableAliasCheck.FindSubmatch(b)
+	if len(tableAliasCheck) > 0 {
+		entity.tableAlias = string(tableAliasCheck[1])
 	}
 
 	entity.hasPrivateNewMethod = bytes.Contains(b, []byte("func new"))
@@ -517,8 +589,9 @@ func getFirstLetterLowercase(s string) string {
 }
 
 // New returns a new instance of Package.
-func New() *Package {
+func New(config *Config) *Package {
 	return &Package{
+		config:                      config,
 		reLowerCase:                 regexp.MustCompile(`[a-z]`),
 		rePropertyLinesCheck:        regexp.MustCompile(`(?m)^\s+([\w_]\w+)\s+(.{2,}?)$`),
 		reSynthesizeOccurrences:     regexp.MustCompile(`\n//\s*@synthesize[\n\s]`),
@@ -527,12 +600,14 @@ func New() *Package {
 		reInterfaceMethodLinesCheck: regexp.MustCompile(`(?m)^\s+(\w+)\((.*?)\)(.*?)$`),
 		reTableNameCheck:            regexp.MustCompile(` TableName\(\) string \{\n\s+return "(.*?)"`),
 		reTableAliasCheck:           regexp.MustCompile(` TableAlias\(\) string \{\n\s+return "(.*?)"`),
+		reEntityAnnotations:         regexp.MustCompile(`(?m)^//\s*@synthesize-([\w-]+)(.*)$`),
 
 		rePublicMethodsCheck: regexp.MustCompile(
 			`(?ms)^func \(\w+ \*\w+\) ([A-Z]\w+)\((.*?)\)( {$| ([^(][^\s]+) {$| \((.*?)\) {$)`),
 		reServicesCheck:          regexp.MustCompile(`\s+(\w+)\s+(\w+\.\w+)`),
 		reImportBlockCheck:       regexp.MustCompile(`(?s)import\ \(\n(.*?)\n\)`),
 		reStoreStructBlockCheck:  regexp.MustCompile(`(?s)type ([a-zA-Z]+) struct \{\n(.*?)\n\}\n`),
+		reStructFieldsCheck:      regexp.MustCompile(`(?m)^\s*(\w+)\s+[\w*\[]`),
 		reImportStatementsCheck:  regexp.MustCompile(`\s+"(.*?)"`),
 		rePackagesInMethodsCheck: regexp.MustCompile(`\w+\.\w+`),
 	}
@@ -0,0 +1,268 @@
+package packages
+
+import (
+	"io"
+	"strconv"
+	"strings"
+)
+
+// DefaultPageSize is the page size the generated pagination uses when none is given.
+const DefaultPageSize = 25
+
+// CursorProperty returns the property keyset pagination orders on. That is the property with a
+// `@synthesize-cursor` comment, otherwise `createdAt` and otherwise the `id`. Cursors can't be
+// nullable, so nil is returned if the choice is a pointer or there is no id to break ties with.
+func (e *Entity) CursorProperty() *Property {
+	id := e.Property("id")
+	if id == nil || !id.IsDatabaseField() || id.IsPointer() {
+		return nil
+	}
+	for _, property := range e.DatabaseProperties() {
+		if _, ok := property.Annotation("cursor"); ok {
+			if property.IsPointer() || property.IsBytes() {
+				return nil
+			}
+			return property
+		}
+	}
+	if createdAt := e.Property("createdAt"); createdAt != nil && createdAt.IsDatabaseField() &&
+		!createdAt.IsPointer() {
+		return createdAt
+	}
+	return id
+}
+
+// cursorValueFormat returns the Go expression that formats the property's value of the entity in
+// the given variable as a string for the cursor.
+func cursorValueFormat(variable string, property *Property) string {
+	return stringFormat(variable+"."+property.name, property._type)
+}
+
+// stringFormat returns the Go expression that formats the value of the given type as a string.
+func stringFormat(value string, _type string) string {
+	switch _type {
+	case "string":
+		return value
+	case "time.Time":
+		return value + ".Format(time.RFC3339Nano)"
+	case "bool":
+		return "strconv.FormatBool(" + value + ")"
+	case "float32", "float64":
+		return "strconv.FormatFloat(float64(" + value + "), 'g', -1, 64)"
+	case "uint", "uint8", "uint16", "uint32", "uint64":
+		return "strconv.FormatUint(uint64(" + value + "), 10)"
+	}
+	return "strconv.FormatInt(int64(" + value + "), 10)"
+}
+
+// paginationMethods returns the generated GetPage method.
+func (s *Store) paginationMethods() []*storeMethod {
+	if s.mainEntity.CursorProperty() == nil {
+		return nil
+	}
+	entityName := s.mainEntity.Name()
+	return []*storeMethod{
+		{
+			function: &Function{
+				name:       "GetPage",
+				parameters: []*FunctionParameter{{name: "opts", _type: entityName + "PageOptions"}},
+				returnValues: []*FunctionReturnValue{
+					{_type: "*" + entityName + "Page"}, {_type: "error"},
+				},
+			},
+			comment:      "GetPage fetches a page of " + entityName + " entities with offset or keyset pagination.",
+			body:         "\treturn " + s.VariableName() + ".Query().Page#CTX(opts)\n",
+			usesDatabase: true,
+		},
+	}
+}
+
+// writePagination writes the page types, the cursor helpers and the query builder's Page method.
+// nolint:funlen
+func (s *Store) writePagination(output io.StringWriter) {
+	entity := s.mainEntity
+	cursorProperty := entity.CursorProperty()
+	if cursorProperty == nil {
+		return
+	}
+	entityName := entity.Name()
+	variable := entity.VariableName()
+	id := entity.Property("id")
+	cursorProperties := []*Property{cursorProperty}
+	if cursorProperty != id {
+		cursorProperties = append(cursorProperties, id)
+	}
+	columns := make([]string, 0, len(cursorProperties))
+	placeholders := make([]string, 0, len(cursorProperties))
+	values := make([]string, 0, len(cursorProperties))
+	for _, property := range cursorProperties {
+		columns = append(columns, entity.TableAlias()+"."+quoteIdentifier(property.ColumnName()))
+		placeholders = append(placeholders, "?::"+property.SQLType())
+		values = append(values, cursorValueFormat(variable, property))
+	}
+	keyset := strings.Join(columns, ", ")
+	keysetPlaceholders := strings.Join(placeholders, ", ")
+	if len(columns) > 1 {
+		keyset = "(" + keyset + ")"
+		keysetPlaceholders = "(" + keysetPlaceholders + ")"
+	}
+
+	output.WriteString("\n")
+	output.WriteString("// " + entityName + "DefaultPageSize is the page size used when the options have no Limit.\n")
+	output.WriteString("const " + entityName + "DefaultPageSize = " + strconv.Itoa(DefaultPageSize) + "\n")
+	output.WriteString("\n")
+	output.WriteString("// ErrInvalidCursor is returned when a page cursor can't be decoded.\n")
+	output.WriteString("var ErrInvalidCursor = errors.New(\"invalid cursor\")\n")
+	output.WriteString("\n")
+	output.WriteString("// ErrOrderedKeysetPage is returned when a query with an OrderBy is paged with a cursor,\n")
+	output.WriteString("// which only works in the cursor's order.\n")
+	output.WriteString("var ErrOrderedKeysetPage = errors.New(" +
+		"\"keyset pagination can't be combined with an OrderBy\")\n")
+
+	output.WriteString("\n")
+	output.WriteString("// " + entityName + "PageOptions selects a page of " + entityName + ". Pages are ordered by " +
+		cursorProperty.name + ".\n")
+	output.WriteString("// A Page number selects offset pagination with a total count, otherwise the\n")
+	output.WriteString("// After or Before cursor of a previous page selects keyset pagination.\n")
+	output.WriteString("type " + entityName + "PageOptions struct {\n")
+	output.WriteString("\tLimit        uint\n")
+	output.WriteString("\tPage         uint\n")
+	output.WriteString("\tAfter        string\n")
+	output.WriteString("\tBefore       string\n")
+	output.WriteString("\tDesc         bool\n")
+	output.WriteString("\tWithCreators bool\n")
+	if entity.SoftDeleteProperty() != nil {
+		output.WriteString("\tIncludeDeleted bool\n")
+	}
+	output.WriteString("}\n")
+
+	output.WriteString("\n")
+	output.WriteString("// " + entityName + "Page is a page of " + entityName + " entities. Total is only counted " +
+		"for offset pagination\n")
+	output.WriteString("// and the cursors are only set for keyset pagination.\n")
+	output.WriteString("type " + entityName + "Page struct {\n")
+	output.WriteString("\tItems      []*" + entityName + "\n")
+	output.WriteString("\tTotal      uint\n")
+	output.WriteString("\tHasNext    bool\n")
+	output.WriteString("\tHasPrev    bool\n")
+	output.WriteString("\tNextCursor string\n")
+	output.WriteString("\tPrevCursor string\n")
+	output.WriteString("}\n")
+
+	output.WriteString("\n")
+	output.WriteString("func encode" + entityName + "Cursor(" + variable + " *" + entityName + ") string {\n")
+	output.WriteString("\treturn base64.RawURLEncoding.EncodeToString([]byte(" +
+		strings.Join(values, " + \"\\x00\" + ") + "))\n")
+	output.WriteString("}\n")
+	output.WriteString("\n")
+	output.WriteString("func decode" + entityName + "Cursor(cursor string) ([]interface{}, error) {\n")
+	output.WriteString("\tdecoded, err := base64.RawURLEncoding.DecodeString(cursor)\n")
+	output.WriteString("\tif err != nil {\n")
+	output.WriteString("\t\treturn nil, errors.Wrap(err, ErrInvalidCursor)\n")
+	output.WriteString("\t}\n")
+	output.WriteString("\tparts := strings.Split(string(decoded), \"\\x00\")\n")
+	output.WriteString("\tif len(parts) != " + strconv.Itoa(len(cursorProperties)) + " {\n")
+	output.WriteString("\t\treturn nil, errors.Trace(ErrInvalidCursor)\n")
+	output.WriteString("\t}\n")
+	output.WriteString("\tvalues := make([]interface{}, len(parts))\n")
+	output.WriteString("\tfor i := range parts {\n")
+	output.WriteString("\t\tvalues[i] = parts[i]\n")
+	output.WriteString("\t}\n")
+	output.WriteString("\treturn values, nil\n")
+	output.WriteString("}\n")
+	output.WriteString("\n")
+	output.WriteString("// clone returns a copy of the query that clauses can be added to without changing q.\n")
+	output.WriteString("func (q *" + s.QueryStructName() + ") clone() *" + s.QueryStructName() + " {\n")
+	output.WriteString("\tclone := *q\n")
+	output.WriteString("\tclone.conditions = append([]string(nil), q.conditions...)\n")
+	output.WriteString("\tclone.params = append([]interface{}(nil), q.params...)\n")
+	output.WriteString("\tclone.orderBy = append([]string(nil), q.orderBy...)\n")
+	output.WriteString("\treturn &clone\n")
+	output.WriteString("}\n")
+
+	body := &strings.Builder{}
+	body.WriteString("\tif len(q.orderBy) > 0 && opts.Page == 0 {\n")
+	body.WriteString("\t\treturn nil, errors.Trace(ErrOrderedKeysetPage)\n")
+	body.WriteString("\t}\n")
+	body.WriteString("\t// Build on a copy so the query can be reused for other pages\n")
+	body.WriteString("\tquery := q.clone()\n")
+	body.WriteString("\tlimit := opts.Limit\n")
+	body.WriteString("\tif limit == 0 {\n")
+	body.WriteString("\t\tlimit = " + entityName + "DefaultPageSize\n")
+	body.WriteString("\t}\n")
+	body.WriteString("\tif opts.WithCreators {\n")
+	body.WriteString("\t\tquery.WithCreators()\n")
+	body.WriteString("\t}\n")
+	if entity.SoftDeleteProperty() != nil {
+		body.WriteString("\tif opts.IncludeDeleted {\n")
+		body.WriteString("\t\tquery.IncludeDeleted()\n")
+		body.WriteString("\t}\n")
+	}
+	body.WriteString("\tpage := &" + entityName + "Page{}\n")
+	body.WriteString("\tif opts.Page > 0 {\n")
+	body.WriteString("\t\ttotal, err := query.Count#CTX_ONLY()\n")
+	body.WriteString("\t\tif err != nil {\n")
+	body.WriteString("\t\t\treturn nil, errors.Trace(err)\n")
+	body.WriteString("\t\t}\n")
+	for _, column := range columns {
+		body.WriteString("\t\tquery.order(`" + column + "`, opts.Desc)\n")
+	}
+	body.WriteString("\t\tquery.Limit(limit).Offset((opts.Page - 1) * limit)\n")
+	body.WriteString("\t\tif page.Items, _, err = query.Fetch#CTX_ONLY(); err != nil {\n")
+	body.WriteString("\t\t\treturn nil, errors.Trace(err)\n")
+	body.WriteString("\t\t}\n")
+	body.WriteString("\t\tpage.Total = total\n")
+	body.WriteString("\t\tpage.HasPrev = opts.Page > 1\n")
+	body.WriteString("\t\tpage.HasNext = opts.Page*limit < total\n")
+	body.WriteString("\t\treturn page, nil\n")
+	body.WriteString("\t}\n")
+	body.WriteString("\n")
+	body.WriteString("\tbackwards := opts.Before != \"\"\n")
+	body.WriteString("\tcursor := opts.After\n")
+	body.WriteString("\tif backwards {\n")
+	body.WriteString("\t\tcursor = opts.Before\n")
+	body.WriteString("\t}\n")
+	body.WriteString("\t// Going back walks the keyset in the opposite order and reverses the results afterwards\n")
+	body.WriteString("\tdesc := backwards != opts.Desc\n")
+	body.WriteString("\tif cursor != \"\" {\n")
+	body.WriteString("\t\tvalues, err := decode" + entityName + "Cursor(cursor)\n")
+	body.WriteString("\t\tif err != nil {\n")
+	body.WriteString("\t\t\treturn nil, errors.Trace(err)\n")
+	body.WriteString("\t\t}\n")
+	body.WriteString("\t\tcomparison := \" > \"\n")
+	body.WriteString("\t\tif desc {\n")
+	body.WriteString("\t\t\tcomparison = \" < \"\n")
+	body.WriteString("\t\t}\n")
+	body.WriteString("\t\tquery.where(`" + keyset + "`+comparison+`" + keysetPlaceholders + "`, values...)\n")
+	body.WriteString("\t}\n")
+	for _, column := range columns {
+		body.WriteString("\tquery.order(`" + column + "`, desc)\n")
+	}
+	body.WriteString("\titems, _, err := query.Limit(limit + 1).Fetch#CTX_ONLY()\n")
+	body.WriteString("\tif err != nil {\n")
+	body.WriteString("\t\treturn nil, errors.Trace(err)\n")
+	body.WriteString("\t}\n")
+	body.WriteString("\tmore := uint(len(items)) > limit\n")
+	body.WriteString("\tif more {\n")
+	body.WriteString("\t\titems = items[:limit]\n")
+	body.WriteString("\t}\n")
+	body.WriteString("\tif backwards {\n")
+	body.WriteString("\t\tfor i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {\n")
+	body.WriteString("\t\t\titems[i], items[j] = items[j], items[i]\n")
+	body.WriteString("\t\t}\n")
+	body.WriteString("\t\tpage.HasPrev, page.HasNext = more, true\n")
+	body.WriteString("\t} else {\n")
+	body.WriteString("\t\tpage.HasPrev, page.HasNext = cursor != \"\", more\n")
+	body.WriteString("\t}\n")
+	body.WriteString("\tpage.Items = items\n")
+	body.WriteString("\tif len(items) > 0 && page.HasPrev {\n")
+	body.WriteString("\t\tpage.PrevCursor = encode" + entityName + "Cursor(items[0])\n")
+	body.WriteString("\t}\n")
+	body.WriteString("\tif len(items) > 0 && page.HasNext {\n")
+	body.WriteString("\t\tpage.NextCursor = encode" + entityName + "Cursor(items[len(items)-1])\n")
+	body.WriteString("\t}\n")
+	body.WriteString("\treturn page, nil\n")
+
+	s.writeQueryRunner(output, "Page", "runs the query for the page the options select, adding the page's "+
+		"ordering and limits", "opts "+entityName+"PageOptions", "(*"+entityName+"Page, error)", body.String())
+}
@@ -0,0 +1,43 @@
+package packages
+
+import (
+	"testing"
+)
+
+func TestStringFormat(t *testing.T) {
+	tests := []struct {
+		_type    string
+		expected string
+	}{
+		{"string", "v"},
+		{"time.Time", "v.Format(time.RFC3339Nano)"},
+		{"bool", "strconv.FormatBool(v)"},
+		{"float32", "strconv.FormatFloat(float64(v), 'g', -1, 64)"},
+		{"float64", "strconv.FormatFloat(float64(v), 'g', -1, 64)"},
+		{"uint8", "strconv.FormatUint(uint64(v), 10)"},
+		{"uint64", "strconv.FormatUint(uint64(v), 10)"},
+		{"int", "strconv.FormatInt(int64(v), 10)"},
+		{"int32", "strconv.FormatInt(int64(v), 10)"},
+	}
+	for _, test := range tests {
+		if actual := stringFormat("v", test._type); actual != test.expected {
+			t.Errorf("stringFormat of %s = %s, expected %s", test._type, actual, test.expected)
+		}
+	}
+}
+
+func TestCursorValueFormat(t *testing.T) {
+	tests := []struct {
+		property *Property
+		expected string
+	}{
+		{&Property{name: "id", _type: "string"}, "u.id"},
+		{&Property{name: "createdAt", _type: "time.Time"}, "u.createdAt.Format(time.RFC3339Nano)"},
+		{&Property{name: "priority", _type: "uint16"}, "strconv.FormatUint(uint64(u.priority), 10)"},
+	}
+	for _, test := range tests {
+		if actual := cursorValueFormat("u", test.property); actual != test.expected {
+			t.Errorf("cursorValueFormat of %s = %s, expected %s", test.property.name, actual, test.expected)
+		}
+	}
+}
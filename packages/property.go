@@ -6,9 +6,11 @@ import (
 
 // Property for an entity structure.
 type Property struct {
-	name    string
-	_type   string
-	comment string
+	name            string
+	_type           string
+	comment         string
+	columnName      string
+	validationRules []*ValidationRule
 }
 
 // Name returns the property's name.
@@ -41,10 +43,53 @@ func (p *Property) SetComment(comment string) {
 	p.comment = comment
 }
 
+// Description returns the property's comment without the annotations, which follow the description.
+func (p *Property) Description() string {
+	if i := strings.Index(p.comment, "@synthesize-"); i >= 0 {
+		return strings.TrimSpace(p.comment[:i])
+	}
+	return p.comment
+}
+
+// HasValidationRule returns if the property has the given validation rule.
+func (p *Property) HasValidationRule(name string) bool {
+	return p.hasValidationRule(name)
+}
+
+// ColumnName returns the property's resolved database column name.
+func (p *Property) ColumnName() string {
+	return p.columnName
+}
+
+// Annotation returns the argument of the `@synthesize-<name>` annotation in the property's comment and if the
+// annotation is present at all.
+func (p *Property) Annotation(name string) (string, bool) {
+	fields := strings.Fields(p.comment)
+	for i, field := range fields {
+		if field != "@synthesize-"+name {
+			continue
+		}
+		if i+1 < len(fields) && !strings.HasPrefix(fields[i+1], "@") {
+			return fields[i+1], true
+		}
+		return "", true
+	}
+	return "", false
+}
+
+// IsDatabaseField returns if the property is backed by a database column.
+func (p *Property) IsDatabaseField() bool {
+	_, ok := p.Annotation("no-db-field")
+	return !ok
+}
+
 // GetterName returns the property's getter method name for the entity.
 func (p *Property) GetterName() string {
-	if p.name == "_type" {
+	switch p.name {
+	case "_type":
 		return "Type"
+	case "id":
+		return "ID"
 	}
 	return strings.Title(p.name)
 }
@@ -56,3 +101,71 @@ func (p *Property) SetterName() string {
 	}
 	return "Set" + strings.Title(p.name)
 }
+
+// IsPointer returns if the property's type is a pointer.
+func (p *Property) IsPointer() bool {
+	return strings.HasPrefix(p._type, "*")
+}
+
+// BaseType returns the property's type without the pointer.
+func (p *Property) BaseType() string {
+	return strings.TrimPrefix(p._type, "*")
+}
+
+// IsNumeric returns if the property's base type is a number.
+func (p *Property) IsNumeric() bool {
+	switch p.BaseType() {
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64",
+		"float32", "float64", "time.Duration":
+		return true
+	}
+	return false
+}
+
+// IsTime returns if the property's base type is a time.Time.
+func (p *Property) IsTime() bool {
+	return p.BaseType() == "time.Time"
+}
+
+// IsString returns if the property's base type is a string.
+func (p *Property) IsString() bool {
+	return p.BaseType() == "string"
+}
+
+// IsBytes returns if the property's type is a byte-array.
+func (p *Property) IsBytes() bool {
+	return p._type == "[]byte"
+}
+
+// SQLType returns the property's column type. String IDs are taken to be UUIDs. The type can be
+// overridden with a `@synthesize-sql-type TYPE` comment.
+// nolint:gocyclo
+func (p *Property) SQLType() string {
+	if sqlType, ok := p.Annotation("sql-type"); ok && sqlType != "" {
+		return strings.ToUpper(sqlType)
+	}
+	switch p.BaseType() {
+	case "string":
+		if p.name == "id" || strings.HasSuffix(p.name, "ID") {
+			return "UUID"
+		}
+		return "TEXT"
+	case "bool":
+		return "BOOLEAN"
+	case "int8", "uint8", "int16":
+		return "SMALLINT"
+	case "uint16", "int32":
+		return "INTEGER"
+	case "int", "int64", "uint", "uint32", "uint64", "time.Duration":
+		return "BIGINT"
+	case "float32":
+		return "REAL"
+	case "float64":
+		return "DOUBLE PRECISION"
+	case "time.Time":
+		return "TIMESTAMP WITH TIME ZONE"
+	case "[]byte":
+		return "BYTEA"
+	}
+	return "TEXT"
+}
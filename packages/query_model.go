@@ -0,0 +1,88 @@
+package packages
+
+// nolint:lll
+const queryModel = `// #QUERY_STRUCT_NAME builds and runs a select query for #ENTITY_STRUCT_NAME on the #STRUCT_NAME.
+type #QUERY_STRUCT_NAME struct {
+	store        *#STRUCT_NAME
+	conditions   []string
+	params       []interface{}
+	orderBy      []string
+	limit        uint
+	offset       uint
+	withCreators bool
+#QUERY_EXTRA_FIELDS}
+
+func (q *#QUERY_STRUCT_NAME) where(condition string, params ...interface{}) *#QUERY_STRUCT_NAME {
+	for _, param := range params {
+		q.params = append(q.params, param)
+		condition = strings.Replace(condition, "?", "$"+strconv.Itoa(len(q.params)), 1)
+	}
+	q.conditions = append(q.conditions, condition)
+	return q
+}
+
+func (q *#QUERY_STRUCT_NAME) whereIn(column string, params ...interface{}) *#QUERY_STRUCT_NAME {
+	if len(params) == 0 {
+		q.conditions = append(q.conditions, "FALSE")
+		return q
+	}
+	placeholders := make([]string, len(params))
+	for i := range params {
+		placeholders[i] = "?"
+	}
+	return q.where(column+" IN ("+strings.Join(placeholders, ", ")+")", params...)
+}
+
+func (q *#QUERY_STRUCT_NAME) order(column string, desc bool) *#QUERY_STRUCT_NAME {
+	if desc {
+		column += " DESC"
+	}
+	q.orderBy = append(q.orderBy, column)
+	return q
+}
+
+// WithCreators joins and fetches the creator and updater names.
+func (q *#QUERY_STRUCT_NAME) WithCreators() *#QUERY_STRUCT_NAME {
+	q.withCreators = true
+	return q
+}
+
+// Limit limits the amount of results.
+func (q *#QUERY_STRUCT_NAME) Limit(limit uint) *#QUERY_STRUCT_NAME {
+	q.limit = limit
+	return q
+}
+
+// Offset skips the given amount of results.
+func (q *#QUERY_STRUCT_NAME) Offset(offset uint) *#QUERY_STRUCT_NAME {
+	q.offset = offset
+	return q
+}
+
+// Build compiles the query to its SQL and parameters.
+func (q *#QUERY_STRUCT_NAME) Build() (string, []interface{}) {
+	return q.build(true)
+}
+
+func (q *#QUERY_STRUCT_NAME) build(withOrderAndLimits bool) (string, []interface{}) {
+	query := &strings.Builder{}
+	query.WriteString(q.store.selectQuery(q.withCreators))
+	conditions := q.conditions
+#QUERY_EXTRA_CONDITIONS	if len(conditions) > 0 {
+		query.WriteString(" WHERE " + strings.Join(conditions, " AND "))
+	}
+	if !withOrderAndLimits {
+		return query.String(), q.params
+	}
+	if len(q.orderBy) > 0 {
+		query.WriteString(" ORDER BY " + strings.Join(q.orderBy, ", "))
+	}
+	if q.limit > 0 {
+		query.WriteString(" LIMIT " + strconv.FormatUint(uint64(q.limit), 10))
+	}
+	if q.offset > 0 {
+		query.WriteString(" OFFSET " + strconv.FormatUint(uint64(q.offset), 10))
+	}
+	return query.String(), q.params
+}
+`
@@ -0,0 +1,296 @@
+package packages
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// RelationKind defines which side of a relation a property is on.
+type RelationKind string
+
+const (
+	// RelationBelongsTo marks a property that holds the ID of another entity.
+	RelationBelongsTo RelationKind = "belongs-to"
+	// RelationHasMany marks a slice property that holds the entities referring back to this entity.
+	RelationHasMany RelationKind = "has-many"
+)
+
+// Relation between an entity's property and another entity.
+type Relation struct {
+	kind     RelationKind
+	property *Property
+	// target is the name of the referenced entity for belongs-to and
+	// the name of the child entity for has-many.
+	target string
+	// foreignKey is the name of the child's property referring back for has-many.
+	foreignKey   string
+	targetEntity *Entity
+}
+
+// Kind returns the relation's kind.
+func (r *Relation) Kind() RelationKind {
+	return r.kind
+}
+
+// Property returns the property carrying the relation annotation.
+func (r *Relation) Property() *Property {
+	return r.property
+}
+
+// Target returns the name of the related entity.
+func (r *Relation) Target() string {
+	return r.target
+}
+
+// TargetEntity returns the related entity if it lives in the same package.
+func (r *Relation) TargetEntity() *Entity {
+	return r.targetEntity
+}
+
+// ForeignKey returns the child's property name that refers back for has-many relations.
+func (r *Relation) ForeignKey() string {
+	return r.foreignKey
+}
+
+// LoaderName returns the name part used for generated loader methods (`userID` becomes `User`).
+func (r *Relation) LoaderName() string {
+	name := r.property.GetterName()
+	if r.kind == RelationBelongsTo && strings.HasSuffix(name, "ID") && len(name) > 2 {
+		return strings.TrimSuffix(name, "ID")
+	}
+	return name
+}
+
+// relationsFromProperties collects the `@synthesize-belongs-to <Entity>` and
+// `@synthesize-has-many <fkProperty>` annotations of the entity's properties.
+func (e *Entity) relationsFromProperties() error {
+	for _, property := range e.properties {
+		if target, ok := property.Annotation(string(RelationBelongsTo)); ok {
+			if target == "" {
+				return errors.Errorf("`%s.%s` belongs-to needs an entity name", e.name, property.name)
+			}
+			e.relations = append(e.relations, &Relation{
+				kind:     RelationBelongsTo,
+				property: property,
+				target:   target,
+			})
+		}
+		if foreignKey, ok := property.Annotation(string(RelationHasMany)); ok {
+			if foreignKey == "" {
+				return errors.Errorf("`%s.%s` has-many needs the child's foreign key property", e.name, property.name)
+			}
+			if !strings.HasPrefix(property._type, "[]*") || property.IsDatabaseField() {
+				return errors.Errorf("`%s.%s` has-many must be a `@synthesize-no-db-field` slice of entity pointers",
+					e.name, property.name)
+			}
+			e.relations = append(e.relations, &Relation{
+				kind:       RelationHasMany,
+				property:   property,
+				target:     strings.TrimPrefix(property._type, "[]*"),
+				foreignKey: foreignKey,
+			})
+		}
+	}
+	return nil
+}
+
+// resolveRelations links the relations to the entities in the same package.
+// Has-many relations can only refer to entities in the same package as the
+// children have to be scanned into their private fields.
+func (p *Package) resolveRelations() error {
+	entities := p.AllEntities()
+	byName := make(map[string]*Entity, len(entities))
+	for _, entity := range entities {
+		byName[entity.name] = entity
+	}
+	for _, entity := range entities {
+		for _, relation := range entity.relations {
+			relation.targetEntity = byName[relation.target]
+			if relation.kind != RelationHasMany {
+				continue
+			}
+			if relation.targetEntity == nil {
+				return errors.Errorf("has-many `%s.%s` refers to `%s` which is not in package `%s`",
+					entity.name, relation.property.name, relation.target, p.name)
+			}
+			if relation.targetEntity.Property(relation.foreignKey) == nil {
+				return errors.Errorf("has-many `%s.%s` refers to non-existing property `%s.%s`",
+					entity.name, relation.property.name, relation.target, relation.foreignKey)
+			}
+		}
+	}
+	return nil
+}
+
+// ForeignKeyConstraints returns the DDL statements for the entity's belongs-to relations.
+func (e *Entity) ForeignKeyConstraints() []string {
+	namingStrategy := e.userNamingStrategy()
+	constraints := []string{}
+	for _, relation := range e.relations {
+		if relation.kind != RelationBelongsTo || !relation.property.IsDatabaseField() {
+			continue
+		}
+		targetTable := namingStrategy.TableName(relation.target)
+		targetID := namingStrategy.ColumnName("id")
+		if relation.targetEntity != nil {
+			targetTable = relation.targetEntity.TableName()
+			if id := relation.targetEntity.Property("id"); id != nil {
+				targetID = id.ColumnName()
+			}
+		}
+		constraints = append(constraints, "ALTER TABLE "+quoteIdentifier(e.TableName())+
+			" ADD CONSTRAINT "+quoteIdentifier("fk_"+e.TableName()+"_"+relation.property.ColumnName())+
+			" FOREIGN KEY ("+quoteIdentifier(relation.property.ColumnName())+") REFERENCES "+
+			quoteIdentifier(targetTable)+" ("+quoteIdentifier(targetID)+");")
+	}
+	return constraints
+}
+
+// ContainsRelationMethods returns if the store gets generated relation loaders.
+func (s *Store) ContainsRelationMethods() bool {
+	for _, relation := range s.mainEntity.relations {
+		if relation.kind == RelationHasMany || relation.property.IsDatabaseField() {
+			return true
+		}
+	}
+	return false
+}
+
+// relationMethods returns the generated relation loaders.
+// nolint:funlen
+func (s *Store) relationMethods() []*storeMethod {
+	methods := []*storeMethod{}
+	entity := s.mainEntity
+	for _, relation := range entity.relations {
+		property := relation.property
+		switch {
+		case relation.kind == RelationBelongsTo && property.IsDatabaseField():
+			baseType := property.BaseType()
+			methods = append(methods, &storeMethod{
+				function: &Function{
+					name:       "GetFor" + relation.LoaderName(),
+					parameters: []*FunctionParameter{{name: property.name, _type: baseType}},
+					returnValues: []*FunctionReturnValue{
+						{_type: "[]*" + entity.Name()}, {_type: "bool"}, {_type: "error"},
+					},
+				},
+				comment: "GetFor" + relation.LoaderName() + " fetches all " + entity.Name() +
+					" entities that belong to the given " + relation.Target() + ".",
+				body: "\treturn " + s.VariableName() + ".Query().Where" + property.GetterName() + "(" +
+					property.name + ").Fetch#CTX_ONLY()\n",
+				usesDatabase: true,
+			})
+
+			body := &strings.Builder{}
+			body.WriteString("\tresult := make(map[" + baseType + "][]*" + entity.Name() + ", len(" +
+				property.name + "s))\n")
+			body.WriteString("\tif len(" + property.name + "s) == 0 {\n")
+			body.WriteString("\t\treturn result, nil\n")
+			body.WriteString("\t}\n")
+			body.WriteString("\tentities, _, err := " + s.VariableName() + ".Query().Where" + property.GetterName() +
+				"In(" + property.name + "s...).Fetch#CTX_ONLY()\n")
+			body.WriteString("\tif err != nil {\n")
+			body.WriteString("\t\treturn nil, errors.Trace(err)\n")
+			body.WriteString("\t}\n")
+			body.WriteString("\tfor _, entity := range entities {\n")
+			if property.IsPointer() {
+				body.WriteString("\t\tif entity." + property.name + " == nil {\n")
+				body.WriteString("\t\t\tcontinue\n")
+				body.WriteString("\t\t}\n")
+				body.WriteString("\t\tresult[*entity." + property.name + "] = append(result[*entity." +
+					property.name + "], entity)\n")
+			} else {
+				body.WriteString("\t\tresult[entity." + property.name + "] = append(result[entity." +
+					property.name + "], entity)\n")
+			}
+			body.WriteString("\t}\n")
+			body.WriteString("\treturn result, nil\n")
+			methods = append(methods, &storeMethod{
+				function: &Function{
+					name:       "GetFor" + relation.LoaderName() + "Batch",
+					parameters: []*FunctionParameter{{name: property.name + "s", _type: "..." + baseType}},
+					returnValues: []*FunctionReturnValue{
+						{_type: "map[" + baseType + "][]*" + entity.Name()}, {_type: "error"},
+					},
+				},
+				comment: "GetFor" + relation.LoaderName() + "Batch fetches the " + entity.Name() +
+					" entities for multiple " + relation.Target() + " entities in one query, grouped by " +
+					property.name + ".",
+				body:         body.String(),
+				usesDatabase: true,
+			})
+		case relation.kind == RelationHasMany:
+			child := relation.targetEntity
+			foreignKey := child.Property(relation.foreignKey)
+			childCondition := ""
+			if condition := child.softDeleteCondition(child.TableAlias()); condition != "" {
+				childCondition = " AND " + condition
+			}
+			body := &strings.Builder{}
+			body.WriteString("\tif len(entities) == 0 {\n")
+			body.WriteString("\t\treturn nil\n")
+			body.WriteString("\t}\n")
+			body.WriteString("\tbyID := make(map[" + entity.idType() + "]*" + entity.Name() + ", len(entities))\n")
+			body.WriteString("\tparams := make([]interface{}, 0, len(entities))\n")
+			body.WriteString("\tplaceholders := make([]string, 0, len(entities))\n")
+			body.WriteString("\tfor _, entity := range entities {\n")
+			body.WriteString("\t\tentity." + property.name + " = nil\n")
+			body.WriteString("\t\tbyID[entity.id] = entity\n")
+			body.WriteString("\t\tparams = append(params, entity.id)\n")
+			body.WriteString("\t\tplaceholders = append(placeholders, \"$\"+strconv.Itoa(len(params)))\n")
+			body.WriteString("\t}\n")
+			body.WriteString("\tchildren, _, err := " + s.VariableName() + ".fetch" + child.Name() +
+				"#CTX(\"SELECT \"+" + child.Name() + "SelectColumns+` FROM " + quoteIdentifier(child.TableName()) +
+				" " + child.TableAlias() + " WHERE " + child.TableAlias() + "." +
+				quoteIdentifier(foreignKey.ColumnName()) + " IN (`+strings.Join(placeholders, \", \")+`)" +
+				childCondition + "`, false, params...)\n")
+			body.WriteString("\tif err != nil {\n")
+			body.WriteString("\t\treturn errors.Trace(err)\n")
+			body.WriteString("\t}\n")
+			body.WriteString("\tfor _, child := range children {\n")
+			if foreignKey.IsPointer() {
+				body.WriteString("\t\tif child." + foreignKey.name + " == nil {\n")
+				body.WriteString("\t\t\tcontinue\n")
+				body.WriteString("\t\t}\n")
+				body.WriteString("\t\tif entity, ok := byID[*child." + foreignKey.name + "]; ok {\n")
+			} else {
+				body.WriteString("\t\tif entity, ok := byID[child." + foreignKey.name + "]; ok {\n")
+			}
+			body.WriteString("\t\t\tentity." + property.name + " = append(entity." + property.name + ", child)\n")
+			body.WriteString("\t\t}\n")
+			body.WriteString("\t}\n")
+			body.WriteString("\treturn nil\n")
+			methods = append(methods, &storeMethod{
+				function: &Function{
+					name:         "Load" + relation.LoaderName(),
+					parameters:   []*FunctionParameter{{name: "entities", _type: "...*" + entity.Name()}},
+					returnValues: []*FunctionReturnValue{{_type: "error"}},
+				},
+				comment:      "Load" + relation.LoaderName() + " loads the " + property.name + " of the given entities in one query.",
+				body:         body.String(),
+				imports:      []string{"strconv", "strings"},
+				usesDatabase: true,
+			})
+		}
+	}
+	return methods
+}
+
+// childEntities returns the same-package entities the store needs a fetch for.
+func (s *Store) childEntities() []*Entity {
+	children := []*Entity{}
+	seen := map[string]bool{}
+	if translation := s.translationEntity(); translation != nil {
+		seen[translation.name] = true
+		children = append(children, translation)
+	}
+	for _, relation := range s.mainEntity.relations {
+		if relation.kind != RelationHasMany || seen[relation.target] {
+			continue
+		}
+		seen[relation.target] = true
+		children = append(children, relation.targetEntity)
+	}
+	return children
+}
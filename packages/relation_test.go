@@ -0,0 +1,40 @@
+package packages
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRelationGeneration(t *testing.T) {
+	tests := []struct {
+		pkg      string
+		file     string
+		expected []string
+	}{
+		{"user", "store_synthesized.go", []string{
+			"func (s *UsersStore) LoadAddresses(entities ...*User) error {",
+			"byID := make(map[string]*User, len(entities))",
+			"children, _, err := s.fetchAddress(\"SELECT \"+AddressSelectColumns+` FROM \"Address\" a " +
+				"WHERE a.\"userID\" IN (`+strings.Join(placeholders, \", \")+`)`, false, params...)",
+			"if entity, ok := byID[child.userID]; ok {",
+			`"strconv"`,
+		}},
+		{"order", "store_synthesized.go", []string{
+			"func (s *OrdersStore) LoadLines(entities ...*Order) error {",
+			"byID := make(map[uint]*Order, len(entities))",
+			"WHERE ol.\"orderID\" IN (",
+		}},
+		{"order", "OrderLine_synthesized.go", []string{
+			"const OrderLineForeignKeysDDL = `ALTER TABLE \"OrderLine\" ADD CONSTRAINT \"fk_OrderLine_orderID\" " +
+				"FOREIGN KEY (\"orderID\") REFERENCES \"order\" (\"id\");`",
+		}},
+	}
+	for _, test := range tests {
+		outputs := buildTestOutputs(t, loadTestPackage(t, test.pkg))
+		for _, expected := range test.expected {
+			if !strings.Contains(outputs[test.file], expected) {
+				t.Errorf("%s %s doesn't contain %s\n%s", test.pkg, test.file, expected, outputs[test.file])
+			}
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package packages
+
+import (
+	"io"
+	"strings"
+)
+
+// SoftDeleteProperty returns the entity's `deletedAt *time.Time` property if it has one, which makes
+// deletes only set the timestamp and the generated queries skip the deleted rows.
+func (e *Entity) SoftDeleteProperty() *Property {
+	property := e.Property("deletedAt")
+	if property == nil || property._type != "*time.Time" || !property.IsDatabaseField() {
+		return nil
+	}
+	return property
+}
+
+// softDeleteCondition returns the condition that excludes the entity's deleted rows, prefixed with
+// the table alias if given. It's empty if the entity isn't soft-deletable.
+func (e *Entity) softDeleteCondition(alias string) string {
+	property := e.SoftDeleteProperty()
+	if property == nil {
+		return ""
+	}
+	if alias != "" {
+		alias += "."
+	}
+	return alias + quoteIdentifier(property.ColumnName()) + " IS NULL"
+}
+
+// writeIsDeleted writes the entity's IsDeleted method.
+func (e *Entity) writeIsDeleted(output io.StringWriter) {
+	output.WriteString("\n")
+	output.WriteString("// IsDeleted returns true if DeletedAt is set.\n")
+	output.WriteString("func (" + e.VariableName() + " *" + e.name + ") IsDeleted() bool {\n")
+	output.WriteString("\treturn " + e.VariableName() + ".deletedAt != nil\n")
+	output.WriteString("}\n")
+}
+
+// softDeleteMethods returns the generated Delete, Restore and HardDelete methods.
+func (s *Store) softDeleteMethods() []*storeMethod {
+	entity := s.mainEntity
+	deletedAt := entity.SoftDeleteProperty()
+	id := entity.Property("id")
+	if deletedAt == nil || id == nil || !id.IsDatabaseField() {
+		return nil
+	}
+	table := quoteIdentifier(entity.TableName())
+	idColumn := quoteIdentifier(id.ColumnName())
+	deletedAtColumn := quoteIdentifier(deletedAt.ColumnName())
+	idsMethod := func(name string, comment string, handle string, query string, condition string) *storeMethod {
+		body := &strings.Builder{}
+		body.WriteString("\tif len(ids) == 0 {\n")
+		body.WriteString("\t\treturn nil\n")
+		body.WriteString("\t}\n")
+		body.WriteString("\tparams := make([]interface{}, len(ids))\n")
+		body.WriteString("\tplaceholders := make([]string, len(ids))\n")
+		body.WriteString("\tfor i, id := range ids {\n")
+		body.WriteString("\t\tparams[i] = id\n")
+		body.WriteString("\t\tplaceholders[i] = \"$\" + strconv.Itoa(i+1)\n")
+		body.WriteString("\t}\n")
+		body.WriteString("\t_, err := " + handle + ".Exec#CTX(`" + query + " WHERE " + idColumn +
+			" IN (`+strings.Join(placeholders, \", \")+`)" + condition + "`, params...)\n")
+		body.WriteString("\treturn errors.Trace(err)\n")
+		return &storeMethod{
+			function: &Function{
+				name:         name,
+				parameters:   []*FunctionParameter{{name: "ids", _type: "..." + id.BaseType()}},
+				returnValues: []*FunctionReturnValue{{_type: "error"}},
+			},
+			comment:      comment,
+			body:         body.String(),
+			imports:      []string{"strconv", "strings"},
+			usesDatabase: true,
+		}
+	}
+
+	methods := []*storeMethod{}
+	if s.databaseField("updaterDatabase") != "" {
+		methods = append(methods,
+			idsMethod("Delete", "Delete soft-deletes the "+entity.Name()+" entities with the given ids.", "#UPDATER",
+				"UPDATE "+table+" SET "+deletedAtColumn+" = NOW()", " AND "+deletedAtColumn+" IS NULL"),
+			idsMethod("Restore", "Restore undoes the soft-delete of the "+entity.Name()+" entities with the given ids.",
+				"#UPDATER", "UPDATE "+table+" SET "+deletedAtColumn+" = NULL", ""))
+	}
+	if s.databaseField("deletorDatabase", "deleterDatabase") != "" {
+		methods = append(methods, idsMethod("HardDelete", "HardDelete permanently deletes the "+entity.Name()+
+			" entities with the given ids, including soft-deleted ones.", "#DELETOR", "DELETE FROM "+table, ""))
+	}
+	return methods
+}
+
+// softDeleteQueryFields returns the query builder's extra struct fields and build conditions
+// for soft-deletable entities.
+func (s *Store) softDeleteQueryFields() (string, string) {
+	condition := s.mainEntity.softDeleteCondition(s.mainEntity.TableAlias())
+	if condition == "" {
+		return "", ""
+	}
+	return "\tincludeDeleted bool\n", "\tif !q.includeDeleted {\n" +
+		"\t\tconditions = append(conditions[:len(conditions):len(conditions)], `" + condition + "`)\n" +
+		"\t}\n"
+}
+
+// writeIncludeDeleted writes the query builder's IncludeDeleted method for soft-deletable entities.
+func (s *Store) writeIncludeDeleted(output io.StringWriter) {
+	if s.mainEntity.SoftDeleteProperty() == nil {
+		return
+	}
+	output.WriteString("\n")
+	output.WriteString("// IncludeDeleted makes the query return soft-deleted results too.\n")
+	output.WriteString("func (q *" + s.QueryStructName() + ") IncludeDeleted() *" + s.QueryStructName() + " {\n")
+	output.WriteString("\tq.includeDeleted = true\n")
+	output.WriteString("\treturn q\n")
+	output.WriteString("}\n")
+}
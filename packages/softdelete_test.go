@@ -0,0 +1,55 @@
+package packages
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSoftDeleteGeneration(t *testing.T) {
+	outputs := buildTestOutputs(t, loadTestPackage(t, "user"))
+	tests := []struct {
+		file     string
+		expected []string
+	}{
+		{"store_synthesized.go", []string{
+			"func (s *UsersStore) Delete(ids ...string) error {",
+			"`UPDATE \"User\" SET \"deletedAt\" = NOW() WHERE \"id\" IN (`",
+			"AND \"deletedAt\" IS NULL`, params...)",
+			"func (s *UsersStore) Restore(ids ...string) error {",
+			"`UPDATE \"User\" SET \"deletedAt\" = NULL WHERE \"id\" IN (`",
+			"func (s *UsersStore) HardDelete(ids ...string) error {",
+			"func (s *UsersStore) iterateIncludingDeleted(query string, withCreators bool, fn func(*User) error, " +
+				"params ...interface{}) (err error) {",
+			"\treturn s.iterateIncludingDeleted(query, withCreators, func(u *User) error {\n" +
+				"\t\tif u.deletedAt != nil {\n" +
+				"\t\t\treturn nil\n" +
+				"\t\t}\n" +
+				"\t\treturn fn(u)\n" +
+				"\t}, params...)\n",
+			"func (s *UsersStore) fetchIncludingDeleted(query string, withCreators bool, params ...interface{}) " +
+				"([]*User, bool, error) {",
+		}},
+		{"query_synthesized.go", []string{
+			"\tif !q.includeDeleted {\n" +
+				"\t\tconditions = append(conditions[:len(conditions):len(conditions)], `ue.\"deletedAt\" IS NULL`)\n",
+			"\treturn q.store.fetchIncludingDeleted(query, q.withCreators, params...)\n",
+			"\treturn q.store.iterateIncludingDeleted(query, q.withCreators, fn, params...)\n",
+		}},
+		{"User_synthesized.go", []string{
+			"func (u *User) IsDeleted() bool {\n\treturn u.deletedAt != nil\n}",
+		}},
+	}
+	for _, test := range tests {
+		for _, expected := range test.expected {
+			if !strings.Contains(outputs[test.file], expected) {
+				t.Errorf("%s doesn't contain %s", test.file, expected)
+			}
+		}
+	}
+
+	// Entities without a deletedAt keep the plain fetchers
+	store := buildTestOutputs(t, loadTestPackage(t, "order"))["store_synthesized.go"]
+	if strings.Contains(store, "IncludingDeleted") || strings.Contains(store, "Restore(") {
+		t.Errorf("order store shouldn't be soft-deletable\n%s", store)
+	}
+}
@@ -2,6 +2,7 @@ package packages
 
 import (
 	"bytes"
+	"io"
 	"strings"
 )
 
@@ -17,6 +18,7 @@ type Store struct {
 	imports    []*Import
 	mainEntity *Entity
 	services   []*Service
+	fields     map[string]bool
 	// properties          []*Property
 	methods             []*Function
 	structName          string
@@ -24,8 +26,7 @@ type Store struct {
 	hasPrivateNewMethod bool
 	hasPublicNewMethod  bool
 	hasBuildQueriesFunc bool
-
-	entityCreatorProperties map[string]bool
+	contextAware        bool
 }
 
 // VariableName returns a variable name the store uses in method bodies.
@@ -62,12 +63,106 @@ func (s *Store) addImport(imp *Import) {
 	s.imports = append(s.imports, imp)
 }
 
+// HasMethod returns if the store already has a hand-written method with the given name.
+func (s *Store) HasMethod(name string) bool {
+	for _, method := range s.methods {
+		if method.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// databaseField returns the first of the given database fields the store struct has, or an empty string.
+// Generated writes use it to find the handle for the job, like the `inserterDatabase` for inserts.
+func (s *Store) databaseField(candidates ...string) string {
+	for _, candidate := range candidates {
+		if s.fields[candidate] {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// QueryStructName returns the name of the generated query builder struct.
+func (s *Store) QueryStructName() string {
+	return s.mainEntity.Name() + "Query"
+}
+
+// synthesizedMethods returns the interface signatures of the synthesized public methods.
+func (s *Store) synthesizedMethods() []*Function {
+	methods := []*Function{}
+	for _, method := range s.storeMethods() {
+		methods = append(methods, method.function)
+		if s.IsContextAware() && method.usesDatabase {
+			methods = append(methods, method.contextFunction())
+		}
+	}
+	return methods
+}
+
+func (s *Store) writeInterfaceMethod(output io.StringWriter, method *Function) {
+	output.WriteString("\t" + method.name + "(")
+	var firstHad bool
+	for _, parameter := range method.parameters {
+		if firstHad {
+			output.WriteString(", ")
+		} else {
+			firstHad = true
+		}
+		output.WriteString(parameter.name + " " + parameter._type)
+	}
+	output.WriteString(")")
+	printWrappingParentheses := len(method.returnValues) > 1 || method.ContainsNamedReturnValue()
+	if printWrappingParentheses {
+		output.WriteString(" (")
+	} else if len(method.returnValues) > 0 {
+		output.WriteString(" ")
+	}
+	firstHad = false
+	for _, returnValue := range method.returnValues {
+		if firstHad {
+			output.WriteString(", ")
+		} else {
+			firstHad = true
+		}
+		if returnValue.name == "" {
+			output.WriteString(returnValue._type)
+		} else {
+			output.WriteString(returnValue.name + " " + returnValue._type)
+		}
+	}
+	if printWrappingParentheses {
+		output.WriteString(")")
+	}
+	output.WriteString("\n")
+}
+
 // BuildFileOutput constructs the full synthesized file output for the current s.
 // nolint:funlen,gocognit,gocyclo
 func (s *Store) BuildFileOutput() ([]byte, error) {
 	output := bytes.NewBufferString("// Code generated by espal-store-synthesizer. DO NOT EDIT.\n")
 	output.WriteString("package " + s._package.name + "\n\n")
 
+	s.addImport(&Import{path: "github.com/juju/errors"})
+	for _, method := range s.storeMethods() {
+		for _, path := range method.imports {
+			s.addImport(&Import{path: path})
+		}
+	}
+	if len(s.transactionMethods()) > 0 {
+		// For the BeginTx of the transaction database
+		s.addImport(&Import{path: "context"})
+		s.addImport(&Import{path: "database/sql"})
+	}
+	if s.mainEntity.VersionProperty() != nil &&
+		strings.Contains(stringFormat("", s.versionConflictIDType()), "strconv.") {
+		s.addImport(&Import{path: "strconv"})
+	}
+	if s.IsContextAware() {
+		s.addImport(&Import{path: "context"})
+	}
+
 	if len(s.imports) > 0 {
 		output.WriteString("import (\n")
 		for _, importChunk := range s.imports {
@@ -81,94 +176,33 @@ func (s *Store) BuildFileOutput() ([]byte, error) {
 	output.WriteString("// Store represents a data interaction object.\n")
 	output.WriteString("type Store interface {\n")
 	for _, method := range s.methods {
-		output.WriteString("\t" + method.name + "(")
-		var firstHad bool
-		for _, parameter := range method.parameters {
-			if firstHad {
-				output.WriteString(", ")
-			} else {
-				firstHad = true
-			}
-			output.WriteString(parameter.name + " " + parameter._type)
-		}
-		output.WriteString(")")
-		printWrappingParentheses := len(method.returnValues) > 1 || method.ContainsNamedReturnValue()
-		if printWrappingParentheses {
-			output.WriteString(" (")
-		} else if len(method.returnValues) > 0 {
-			output.WriteString(" ")
-		}
-		firstHad = false
-		for _, returnValue := range method.returnValues {
-			if firstHad {
-				output.WriteString(", ")
-			} else {
-				firstHad = true
-			}
-			if returnValue.name == "" {
-				output.WriteString(returnValue._type)
-			} else {
-				output.WriteString(returnValue.name + " " + returnValue._type)
-			}
-		}
-		if printWrappingParentheses {
-			output.WriteString(")")
-		}
-		output.WriteString("\n")
+		s.writeInterfaceMethod(output, method)
+	}
+	for _, method := range s.synthesizedMethods() {
+		s.writeInterfaceMethod(output, method)
 	}
-	// TODO :: 7777 Also loop Public methods here that are created later (GetOne, etc.)
 	output.WriteString("}\n")
 
-	if !s.ContainsFetchMethod() { // nolint:nestif
-		output.WriteString("\n")
-		// #STRUCT_VAR_NAME			users
-		// #STRUCT_NAME				Users
-		// #ENTITY_STRUCT_NAME		User
-		// #ENTITY_STRUCT_INTERFACE	User
-		// #ENTITY_STRUCT_VAR_NAME  user
-		// #ENTITY_FIELDS			a, b, c
-		// #ENTITY_CREATOR_FIELDS	d, e, f
-		// TODO :: Should not accidentally link fields that aren't DB-fields (extra struct fields)
-		entityFields := strings.Builder{}
-		var firstHad bool
-		for _, property := range s.mainEntity.properties {
-			if _, ok := s.entityCreatorProperties[property.Name()]; ok {
-				continue
-			}
-			if strings.Contains(property.Comment(), "@synthesize-no-db-field") {
-				continue
-			}
-			if firstHad {
-				entityFields.WriteString(", ")
-			}
-			entityFields.WriteString("&" + s.mainEntity.VariableName() + "." + property.Name())
-			firstHad = true
-		}
-		firstHad = false
-		creatorFields := strings.Builder{}
-		for _, property := range s.mainEntity.properties {
-			if _, ok := s.entityCreatorProperties[property.Name()]; !ok {
-				continue
-			}
-			if firstHad {
-				creatorFields.WriteString(", ")
-			}
-			creatorFields.WriteString("&" + s.mainEntity.VariableName() + "." + property.Name())
-			firstHad = true
-		}
+	output.WriteString("\n")
+	output.WriteString("// ErrStopIteration can be returned from an iterate or Each callback to stop without an error.\n")
+	output.WriteString("var ErrStopIteration = errors.New(\"stop iteration\")\n")
 
-		replacer := strings.NewReplacer([]string{
-			"#STRUCT_VAR_NAME", s.VariableName(),
-			"#STRUCT_NAME", s.structName,
-			"#ENTITY_STRUCT_NAME", s.mainEntity.Name(),
-			"#ENTITY_STRUCT_INTERFACE", s.mainEntity.interfaceName,
-			"#ENTITY_STRUCT_VAR_NAME", s.mainEntity.VariableName(),
-			"#ENTITY_FIELDS", entityFields.String(),
-			"#ENTITY_CREATOR_FIELDS", creatorFields.String(),
-		}...)
-		output.WriteString(replacer.Replace(fetchModel))
+	s.writeFetchers(output, s.mainEntity, "", !s.ContainsFetchMethod())
+	output.WriteString("\n")
+	s.writeCount(output)
+	for _, child := range s.childEntities() {
+		s.writeFetchers(output, child, child.Name(), true)
 	}
 
+	output.WriteString("\n")
+	s.writeSelectQuery(output)
+	for _, method := range s.storeMethods() {
+		s.writeStoreMethod(output, method)
+	}
+	s.writeTranslationResolver(output)
+	s.writeVersionConflictError(output)
+	s.writeTransactionDatabase(output)
+
 	if !s.hasPublicNewMethod { // nolint:nestif
 		output.WriteString("\n")
 		output.WriteString("// New returns a new instance of " + s.structName + ".\n")
@@ -229,6 +263,324 @@ func (s *Store) BuildFileOutput() ([]byte, error) {
 	return output.Bytes(), nil
 }
 
+// BuildQueryFileOutput constructs the synthesized query builder file output for the current s.
+func (s *Store) BuildQueryFileOutput() ([]byte, error) {
+	output := bytes.NewBufferString("// Code generated by espal-store-synthesizer. DO NOT EDIT.\n")
+	output.WriteString("package " + s._package.name + "\n\n")
+
+	output.WriteString("import (\n")
+	if s.IsContextAware() {
+		output.WriteString("\t" + `"context"` + "\n")
+	}
+	if s.mainEntity.CursorProperty() != nil {
+		output.WriteString("\t" + `"encoding/base64"` + "\n")
+		output.WriteString("\t" + `"github.com/juju/errors"` + "\n")
+	}
+	output.WriteString("\t" + `"strconv"` + "\n")
+	output.WriteString("\t" + `"strings"` + "\n")
+	for _, property := range s.mainEntity.DatabaseProperties() {
+		if strings.HasPrefix(property.BaseType(), "time.") {
+			output.WriteString("\t" + `"time"` + "\n")
+			break
+		}
+	}
+	output.WriteString(")\n\n")
+
+	entityName := s.mainEntity.Name()
+	extraFields, extraConditions := s.softDeleteQueryFields()
+	replacer := strings.NewReplacer([]string{
+		"#QUERY_EXTRA_FIELDS", extraFields,
+		"#QUERY_EXTRA_CONDITIONS", extraConditions,
+		"#STRUCT_VAR_NAME", s.VariableName(),
+		"#STRUCT_NAME", s.structName,
+		"#ENTITY_STRUCT_NAME", s.mainEntity.Name(),
+		"#QUERY_STRUCT_NAME", s.QueryStructName(),
+	}...)
+	output.WriteString(replacer.Replace(queryModel))
+	s.writeIncludeDeleted(output)
+
+	// The query itself leaves out the soft-deleted rows, unless they're included
+	fetchersSuffix := ""
+	if s.mainEntity.SoftDeleteProperty() != nil {
+		fetchersSuffix = "IncludingDeleted"
+	}
+	s.writeQueryRunner(output, "Fetch", "runs the query and returns the results", "",
+		"([]*"+entityName+", bool, error)", "\tquery, params := q.Build()\n"+
+			"\treturn q.store.fetch"+fetchersSuffix+"#CTX(query, q.withCreators, params...)\n")
+	s.writeQueryRunner(output, "Each", "runs the query and calls fn for each result as it is scanned, "+
+		"stopping at the first error", "fn func(*"+entityName+") error", "error",
+		"\tquery, params := q.Build()\n"+
+			"\treturn q.store.iterate"+fetchersSuffix+"#CTX(query, q.withCreators, fn, params...)\n")
+	s.writeQueryRunner(output, "Count", "returns the amount of results the query has without its limit and offset",
+		"", "(uint, error)", "\tquery, params := q.build(false)\n"+
+			"\treturn q.store.count#CTX(\"SELECT COUNT(*) FROM (\"+query+\") AS counted\", params...)\n")
+
+	for _, property := range s.mainEntity.DatabaseProperties() {
+		s.writeQueryPredicates(output, property)
+	}
+	s.writePagination(output)
+
+	return output.Bytes(), nil
+}
+
+// writeQueryRunner writes a query builder method that builds the query and runs it with the given body,
+// plus its Context variant for context-aware stores.
+func (s *Store) writeQueryRunner(output io.StringWriter, name string, description string, params string,
+	returnValues string, body string) {
+	arguments := ""
+	if params != "" {
+		arguments = strings.SplitN(params, " ", 2)[0]
+	}
+	receiver := "func (q *" + s.QueryStructName() + ") "
+	output.WriteString("\n")
+	output.WriteString("// " + name + " " + description + ".\n")
+	output.WriteString(receiver + name + "(" + params + ") " + returnValues + " {\n")
+	if s.IsContextAware() {
+		if arguments != "" {
+			arguments = ", " + arguments
+		}
+		output.WriteString("\treturn q." + name + "Context(context.Background()" + arguments + ")\n")
+		output.WriteString("}\n")
+		output.WriteString("\n")
+		output.WriteString("// " + name + "Context is the context-aware variant of " + name + ".\n")
+		if params != "" {
+			params = ", " + params
+		}
+		output.WriteString(receiver + name + "Context(ctx context.Context" + params + ") " + returnValues + " {\n")
+	}
+	output.WriteString(s.replaceMethodPlaceholders(body, s.IsContextAware()))
+	output.WriteString("}\n")
+}
+
+// writeFetchers writes the iterate and, if asked for, fetch methods of the given entity, with the
+// name suffix for entities other than the main one. For soft-deletable entities these skip the
+// deleted rows, whatever the query, and IncludingDeleted variants are written that don't.
+func (s *Store) writeFetchers(output io.StringWriter, entity *Entity, suffix string, withFetch bool) {
+	iterateName := "iterate" + suffix
+	if deletedAt := entity.SoftDeleteProperty(); deletedAt != nil {
+		output.WriteString("\n")
+		s.writeIterate(output, entity, iterateName+"IncludingDeleted")
+		output.WriteString("\n")
+		s.writeUndeletedIterate(output, entity, iterateName, deletedAt)
+		output.WriteString("\n")
+		s.writeFetch(output, entity, "fetch"+suffix+"IncludingDeleted")
+	} else {
+		output.WriteString("\n")
+		s.writeIterate(output, entity, iterateName)
+	}
+	if withFetch {
+		output.WriteString("\n")
+		s.writeFetch(output, entity, "fetch"+suffix)
+	}
+}
+
+// writeIterate writes the iterate method that scans the given entity's rows one by one.
+func (s *Store) writeIterate(output io.StringWriter, entity *Entity, name string) {
+	// #ITERATE_NAME			iterate
+	// #STRUCT_VAR_NAME			users
+	// #STRUCT_NAME				Users
+	// #ENTITY_STRUCT_NAME		User
+	// #ENTITY_STRUCT_VAR_NAME  user
+	// #ENTITY_FIELDS			a, b, c
+	// #ENTITY_CREATOR_FIELDS	d, e, f
+	entityFields := strings.Builder{}
+	var firstHad bool
+	for _, property := range entity.DatabaseProperties() {
+		if firstHad {
+			entityFields.WriteString(", ")
+		}
+		entityFields.WriteString("&" + entity.VariableName() + "." + property.Name())
+		firstHad = true
+	}
+	firstHad = false
+	creatorFields := strings.Builder{}
+	for _, property := range entity.CreatorProperties() {
+		if firstHad {
+			creatorFields.WriteString(", ")
+		}
+		creatorFields.WriteString("&" + entity.VariableName() + "." + property.Name())
+		firstHad = true
+	}
+
+	name = s.writeFetchContextWrapper(output, name,
+		"query string, withCreators bool, fn func(*"+entity.Name()+") error, params ...interface{}",
+		"query, withCreators, fn, params...", "error")
+	replacer := strings.NewReplacer([]string{
+		"#ITERATE_NAME", name,
+		"#FETCH_PARAMS", s.fetchParams(),
+		"#STRUCT_VAR_NAME", s.VariableName(),
+		"#STRUCT_NAME", s.structName,
+		"#ENTITY_STRUCT_NAME", entity.Name(),
+		"#ENTITY_STRUCT_VAR_NAME", entity.VariableName(),
+		"#ENTITY_FIELDS", entityFields.String(),
+		"#ENTITY_CREATOR_FIELDS", creatorFields.String(),
+	}...)
+	output.WriteString(s.replaceMethodPlaceholders(replacer.Replace(iterateModel), s.IsContextAware()))
+}
+
+// writeUndeletedIterate writes the iterate method that skips the soft-deleted rows of its
+// IncludingDeleted variant.
+func (s *Store) writeUndeletedIterate(output io.StringWriter, entity *Entity, name string, deletedAt *Property) {
+	includingDeletedName := name + "IncludingDeleted"
+	name = s.writeFetchContextWrapper(output, name,
+		"query string, withCreators bool, fn func(*"+entity.Name()+") error, params ...interface{}",
+		"query, withCreators, fn, params...", "error")
+	replacer := strings.NewReplacer([]string{
+		"#ITERATE_INCLUDING_DELETED_NAME", includingDeletedName,
+		"#ITERATE_NAME", name,
+		"#FETCH_PARAMS", s.fetchParams(),
+		"#STRUCT_VAR_NAME", s.VariableName(),
+		"#STRUCT_NAME", s.structName,
+		"#ENTITY_STRUCT_NAME", entity.Name(),
+		"#ENTITY_STRUCT_VAR_NAME", entity.VariableName(),
+		"#DELETED_AT", deletedAt.name,
+	}...)
+	output.WriteString(s.replaceMethodPlaceholders(replacer.Replace(undeletedIterateModel), s.IsContextAware()))
+}
+
+// writeFetch writes the fetch method that collects the given entity's rows from its iterate method.
+func (s *Store) writeFetch(output io.StringWriter, entity *Entity, name string) {
+	iterateName := "iterate" + strings.TrimPrefix(name, "fetch")
+	name = s.writeFetchContextWrapper(output, name, "query string, withCreators bool, params ...interface{}",
+		"query, withCreators, params...", "([]*"+entity.Name()+", bool, error)")
+	replacer := strings.NewReplacer([]string{
+		"#FETCH_NAME", name,
+		"#ITERATE_NAME", iterateName,
+		"#FETCH_PARAMS", s.fetchParams(),
+		"#STRUCT_VAR_NAME", s.VariableName(),
+		"#STRUCT_NAME", s.structName,
+		"#ENTITY_STRUCT_NAME", entity.Name(),
+		"#ENTITY_STRUCT_VAR_NAME", entity.VariableName(),
+	}...)
+	output.WriteString(s.replaceMethodPlaceholders(replacer.Replace(fetchModel), s.IsContextAware()))
+}
+
+// writeCount writes the count method that runs a COUNT query.
+func (s *Store) writeCount(output io.StringWriter) {
+	name := s.writeFetchContextWrapper(output, "count", "query string, params ...interface{}", "query, params...",
+		"(uint, error)")
+	replacer := strings.NewReplacer([]string{
+		"#COUNT_NAME", name,
+		"#FETCH_PARAMS", s.fetchParams(),
+		"#STRUCT_VAR_NAME", s.VariableName(),
+		"#STRUCT_NAME", s.structName,
+	}...)
+	output.WriteString(s.replaceMethodPlaceholders(replacer.Replace(countModel), s.IsContextAware()))
+}
+
+// writeFetchContextWrapper writes the plain variant of a context-aware store's fetch, iterate or count
+// method, which calls the Context variant with a background context, and returns the name to write
+// the Context variant under. Other stores only get the plain method, so the name is returned as is.
+func (s *Store) writeFetchContextWrapper(output io.StringWriter, name string, params string, arguments string,
+	returnValues string) string {
+	if !s.IsContextAware() {
+		return name
+	}
+	output.WriteString("func (" + s.VariableName() + " *" + s.structName + ") " + name +
+		"(" + params + ") " + returnValues + " {\n")
+	output.WriteString("\treturn " + s.VariableName() + "." + name +
+		"Context(context.Background(), " + arguments + ")\n")
+	output.WriteString("}\n\n")
+	return name + "Context"
+}
+
+// fetchParams returns the leading parameters of the fetch methods.
+func (s *Store) fetchParams() string {
+	if s.IsContextAware() {
+		return "ctx context.Context, "
+	}
+	return ""
+}
+
+// writeSelectQuery writes the helper that returns the SELECT and FROM clauses for the main entity,
+// so queries can be embedded after it and stay in sync with what fetch scans.
+func (s *Store) writeSelectQuery(output io.StringWriter) {
+	from := " FROM " + quoteIdentifier(s.mainEntity.TableName()) + " " + s.mainEntity.TableAlias()
+	output.WriteString("// selectQuery returns the SELECT and FROM clauses for " + s.mainEntity.Name() +
+		", joining the creators if requested.\n")
+	output.WriteString("func (" + s.VariableName() + " *" + s.structName + ") selectQuery(withCreators bool) string {\n")
+	if len(s.mainEntity.CreatorProperties()) > 0 {
+		output.WriteString("\tif withCreators {\n")
+		output.WriteString("\t\treturn \"SELECT \" + " + s.mainEntity.Name() + "SelectColumnsWithCreators + `" + from +
+			" ` + " + s.mainEntity.Name() + "CreatorsJoin\n")
+		output.WriteString("\t}\n")
+	}
+	output.WriteString("\treturn \"SELECT \" + " + s.mainEntity.Name() + "SelectColumns + `" + from + "`\n")
+	output.WriteString("}\n")
+}
+
+// writeQueryPredicates writes the query builder's Where and OrderBy methods for the given property.
+// Every type can be compared for (in)equality, numbers and times can be compared by size,
+// strings can be matched with LIKE and pointers can be checked for NULL.
+// nolint:funlen
+func (s *Store) writeQueryPredicates(output io.StringWriter, property *Property) {
+	queryStruct := "*" + s.QueryStructName()
+	column := s.mainEntity.TableAlias() + "." + quoteIdentifier(property.ColumnName())
+	method := "func (q " + queryStruct + ") "
+	getter := property.GetterName()
+
+	type predicate struct {
+		suffix   string
+		operator string
+		comment  string
+	}
+	predicates := []*predicate{
+		{suffix: "", operator: "=", comment: "is equal to"},
+		{suffix: "Not", operator: "<>", comment: "is not equal to"},
+	}
+	if property.IsNumeric() || property.IsTime() {
+		predicates = append(predicates,
+			&predicate{suffix: "GreaterThan", operator: ">", comment: "is greater than"},
+			&predicate{suffix: "GreaterOrEqual", operator: ">=", comment: "is greater than or equal to"},
+			&predicate{suffix: "LessThan", operator: "<", comment: "is less than"},
+			&predicate{suffix: "LessOrEqual", operator: "<=", comment: "is less than or equal to"},
+		)
+	}
+	if property.IsString() {
+		predicates = append(predicates, &predicate{suffix: "Like", operator: "LIKE", comment: "matches the pattern"})
+	}
+	for _, predicate := range predicates {
+		output.WriteString("\n")
+		output.WriteString("// Where" + getter + predicate.suffix + " filters on " + property.Name() + " " +
+			predicate.comment + " the given value.\n")
+		output.WriteString(method + "Where" + getter + predicate.suffix + "(value " + property.BaseType() + ") " +
+			queryStruct + " {\n")
+		output.WriteString("\treturn q.where(`" + column + " " + predicate.operator + " ?`, value)\n")
+		output.WriteString("}\n")
+	}
+
+	if !property.IsBytes() {
+		output.WriteString("\n")
+		output.WriteString("// Where" + getter + "In filters on " + property.Name() + " being one of the given values.\n")
+		output.WriteString(method + "Where" + getter + "In(values ..." + property.BaseType() + ") " + queryStruct + " {\n")
+		output.WriteString("\tparams := make([]interface{}, len(values))\n")
+		output.WriteString("\tfor i := range values {\n")
+		output.WriteString("\t\tparams[i] = values[i]\n")
+		output.WriteString("\t}\n")
+		output.WriteString("\treturn q.whereIn(`" + column + "`, params...)\n")
+		output.WriteString("}\n")
+	}
+
+	if property.IsPointer() {
+		output.WriteString("\n")
+		output.WriteString("// Where" + getter + "IsNull filters on " + property.Name() + " being NULL.\n")
+		output.WriteString(method + "Where" + getter + "IsNull() " + queryStruct + " {\n")
+		output.WriteString("\treturn q.where(`" + column + " IS NULL`)\n")
+		output.WriteString("}\n")
+		output.WriteString("\n")
+		output.WriteString("// Where" + getter + "IsNotNull filters on " + property.Name() + " not being NULL.\n")
+		output.WriteString(method + "Where" + getter + "IsNotNull() " + queryStruct + " {\n")
+		output.WriteString("\treturn q.where(`" + column + " IS NOT NULL`)\n")
+		output.WriteString("}\n")
+	}
+
+	output.WriteString("\n")
+	output.WriteString("// OrderBy" + getter + " orders the results by " + property.Name() + ".\n")
+	output.WriteString(method + "OrderBy" + getter + "(desc bool) " + queryStruct + " {\n")
+	output.WriteString("\treturn q.order(`" + column + "`, desc)\n")
+	output.WriteString("}\n")
+}
+
 // BuildTestFileOutput constructs the full synthesized test file output for the current entity.
 func (s *Store) BuildTestFileOutput() ([]byte, error) {
 	output := bytes.NewBufferString("// Code generated by espal-store-synthesizer. DO NOT EDIT.\n")
@@ -246,11 +598,6 @@ func newStore(pkg *Package, mainEntity *Entity, hasPrivateNewMethod bool, hasPub
 		hasPrivateNewMethod: hasPrivateNewMethod,
 		hasPublicNewMethod:  hasPublicNewMethod,
 		hasBuildQueriesFunc: hasBuildQueriesFunc,
-		entityCreatorProperties: map[string]bool{
-			"createdByFirstName": true,
-			"createdBySurname":   true,
-			"updatedByFirstName": true,
-			"updatedBySurname":   true,
-		},
+		fields:              map[string]bool{},
 	}
 }
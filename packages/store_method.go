@@ -0,0 +1,176 @@
+package packages
+
+import (
+	"io"
+	"strings"
+)
+
+// storeMethod is a synthesized public Store method. The body can use the #SELECTER, #INSERTER,
+// #UPDATER and #DELETOR placeholders for the database handles and `#CTX(`, `#CTX_ONLY()` and
+// `#BEGIN()` for calls that have a Context variant, so the same method can be written with or
+// without context.Context.
+type storeMethod struct {
+	function *Function
+	comment  string
+	body     string
+	// imports lists the packages the body uses, other than errors.
+	imports []string
+	// usesDatabase methods get a Context variant when the store is context-aware.
+	usesDatabase bool
+}
+
+// signature returns the method's name, parameters and return values as written in Go.
+func (m *storeMethod) signature(name string, withContext bool) string {
+	output := &strings.Builder{}
+	output.WriteString(name + "(")
+	if withContext {
+		output.WriteString("ctx context.Context")
+	}
+	for i, parameter := range m.function.parameters {
+		if i > 0 || withContext {
+			output.WriteString(", ")
+		}
+		output.WriteString(parameter.name + " " + parameter._type)
+	}
+	output.WriteString(")")
+	switch len(m.function.returnValues) {
+	case 0:
+	case 1:
+		output.WriteString(" " + m.function.returnValues[0]._type)
+	default:
+		output.WriteString(" (")
+		for i, returnValue := range m.function.returnValues {
+			if i > 0 {
+				output.WriteString(", ")
+			}
+			output.WriteString(returnValue._type)
+		}
+		output.WriteString(")")
+	}
+	return output.String()
+}
+
+// arguments returns the method's parameters as arguments to pass on to another call.
+func (m *storeMethod) arguments() string {
+	arguments := make([]string, 0, len(m.function.parameters))
+	for _, parameter := range m.function.parameters {
+		if strings.HasPrefix(parameter._type, "...") {
+			arguments = append(arguments, parameter.name+"...")
+		} else {
+			arguments = append(arguments, parameter.name)
+		}
+	}
+	return strings.Join(arguments, ", ")
+}
+
+// contextFunction returns the interface signature of the method's Context variant.
+func (m *storeMethod) contextFunction() *Function {
+	return &Function{
+		name: m.function.name + "Context",
+		parameters: append([]*FunctionParameter{{name: "ctx", _type: "context.Context"}},
+			m.function.parameters...),
+		returnValues: m.function.returnValues,
+	}
+}
+
+// IsContextAware returns if the store opted in to context.Context variants of the
+// synthesized methods with a `// @synthesize-context` annotation.
+func (s *Store) IsContextAware() bool {
+	return s.contextAware
+}
+
+// storeMethods returns all the synthesized public methods, skipping those that are already hand-written.
+func (s *Store) storeMethods() []*storeMethod {
+	candidates := []*storeMethod{
+		{
+			function: &Function{
+				name:         "Query",
+				returnValues: []*FunctionReturnValue{{_type: "*" + s.QueryStructName()}},
+			},
+			comment: "Query returns a new query builder for " + s.mainEntity.Name() + ".",
+			body:    "\treturn &" + s.QueryStructName() + "{store: " + s.VariableName() + "}\n",
+		},
+		{
+			function: &Function{
+				name:         "Each",
+				parameters:   []*FunctionParameter{{name: "fn", _type: "func(*" + s.mainEntity.Name() + ") error"}},
+				returnValues: []*FunctionReturnValue{{_type: "error"}},
+			},
+			comment: "Each calls fn for every " + s.mainEntity.Name() + " as it is scanned. " +
+				"Return ErrStopIteration from fn to stop early.",
+			body:         "\treturn " + s.VariableName() + ".Query().Each#CTX(fn)\n",
+			usesDatabase: true,
+		},
+	}
+	candidates = append(candidates, s.paginationMethods()...)
+	candidates = append(candidates, s.relationMethods()...)
+	candidates = append(candidates, s.translationMethods()...)
+	candidates = append(candidates, s.batchMethods()...)
+	candidates = append(candidates, s.softDeleteMethods()...)
+	candidates = append(candidates, s.changeTrackingMethods()...)
+	candidates = append(candidates, s.transactionMethods()...)
+
+	methods := make([]*storeMethod, 0, len(candidates))
+	for _, candidate := range candidates {
+		if s.HasMethod(candidate.function.name) {
+			continue
+		}
+		if s.IsContextAware() && candidate.usesDatabase && s.HasMethod(candidate.function.name+"Context") {
+			continue
+		}
+		methods = append(methods, candidate)
+	}
+	return methods
+}
+
+// writeStoreMethod writes the method and, for context-aware stores, its Context variant that the
+// plain method then calls with a background context.
+func (s *Store) writeStoreMethod(output io.StringWriter, method *storeMethod) {
+	receiver := "func (" + s.VariableName() + " *" + s.structName + ") "
+	name := method.function.name
+	if !s.IsContextAware() || !method.usesDatabase {
+		output.WriteString("\n")
+		output.WriteString("// " + method.comment + "\n")
+		output.WriteString(receiver + method.signature(name, false) + " {\n")
+		output.WriteString(s.replaceMethodPlaceholders(method.body, false))
+		output.WriteString("}\n")
+		return
+	}
+
+	output.WriteString("\n")
+	output.WriteString("// " + method.comment + "\n")
+	output.WriteString(receiver + method.signature(name, false) + " {\n")
+	output.WriteString("\t")
+	if len(method.function.returnValues) > 0 {
+		output.WriteString("return ")
+	}
+	output.WriteString(s.VariableName() + "." + name + "Context(context.Background()")
+	if arguments := method.arguments(); arguments != "" {
+		output.WriteString(", " + arguments)
+	}
+	output.WriteString(")\n")
+	output.WriteString("}\n")
+
+	output.WriteString("\n")
+	output.WriteString("// " + name + "Context is the context-aware variant of " + name + ".\n")
+	output.WriteString(receiver + method.signature(name+"Context", true) + " {\n")
+	output.WriteString(s.replaceMethodPlaceholders(method.body, true))
+	output.WriteString("}\n")
+}
+
+// replaceMethodPlaceholders fills in the storeMethod body placeholders.
+func (s *Store) replaceMethodPlaceholders(body string, withContext bool) string {
+	ctx, ctxOnly, begin := "(", "()", "Begin()"
+	if withContext {
+		ctx, ctxOnly, begin = "Context(ctx, ", "Context(ctx)", "BeginTx(ctx, nil)"
+	}
+	return strings.NewReplacer(
+		"#SELECTER", s.VariableName()+".selecterDatabase",
+		"#INSERTER", s.VariableName()+"."+s.databaseField("inserterDatabase", "updaterDatabase"),
+		"#UPDATER", s.VariableName()+"."+s.databaseField("updaterDatabase"),
+		"#DELETOR", s.VariableName()+"."+s.databaseField("deletorDatabase", "deleterDatabase"),
+		"#CTX(", ctx,
+		"#CTX_ONLY()", ctxOnly,
+		"#BEGIN()", begin,
+	).Replace(body)
+}
@@ -0,0 +1,45 @@
+package packages
+
+import (
+	"go/format"
+	"testing"
+
+	"github.com/juju/errors"
+)
+
+// loadTestPackage builds the package of the given name from the stores in the testdata directory.
+func loadTestPackage(t *testing.T, name string) *Package {
+	t.Helper()
+	pkg := New(&Config{NamingStrategy: NamingAsIs})
+	if err := pkg.BuildMetaData("../testdata/stores/" + name); err != nil {
+		t.Fatal(errors.ErrorStack(err))
+	}
+	return pkg
+}
+
+// buildTestOutputs synthesizes all the files of the package, keyed by their file name, and checks
+// that they're valid Go.
+func buildTestOutputs(t *testing.T, pkg *Package) map[string]string {
+	t.Helper()
+	builders := map[string]func() ([]byte, error){
+		"store_synthesized.go":    pkg.Store().BuildFileOutput,
+		"query_synthesized.go":    pkg.Store().BuildQueryFileOutput,
+		"fixtures_synthesized.go": pkg.BuildFixtureFileOutput,
+	}
+	for _, entity := range pkg.AllEntities() {
+		builders[entity.Name()+"_synthesized.go"] = entity.BuildFileOutput
+		builders[entity.Name()+"_synthesized_test.go"] = entity.BuildTestFileOutput
+	}
+	outputs := make(map[string]string, len(builders))
+	for name, build := range builders {
+		output, err := build()
+		if err != nil {
+			t.Fatalf("%s: %s", name, errors.ErrorStack(err))
+		}
+		if _, err := format.Source(output); err != nil {
+			t.Fatalf("%s isn't valid Go: %v\n%s", name, err, output)
+		}
+		outputs[name] = string(output)
+	}
+	return outputs
+}
@@ -0,0 +1,100 @@
+package packages
+
+import (
+	"io"
+	"strings"
+)
+
+// transactionDatabaseFields are the store's database fields that WithTx points to the transaction.
+var transactionDatabaseFields = []string{
+	"selecterDatabase", "inserterDatabase", "updaterDatabase", "deletorDatabase", "deleterDatabase",
+}
+
+// TxDatabaseName returns the name of the generated database that runs the store's queries in a transaction.
+func (s *Store) TxDatabaseName() string {
+	return getFirstLetterLowercase(s.structName) + s.structName[1:] + "TxDatabase"
+}
+
+// transactionMethods returns the generated WithTx and RunInTx methods.
+func (s *Store) transactionMethods() []*storeMethod {
+	beginDatabase := s.databaseField("updaterDatabase", "inserterDatabase", "selecterDatabase")
+	if beginDatabase == "" {
+		return nil
+	}
+	v := s.VariableName()
+
+	withTxBody := &strings.Builder{}
+	withTxBody.WriteString("\ttxStore := *" + v + "\n")
+	for _, field := range transactionDatabaseFields {
+		if s.databaseField(field) != "" {
+			withTxBody.WriteString("\ttxStore." + field + " = " + s.TxDatabaseName() + "{Transaction: tx}\n")
+		}
+	}
+	withTxBody.WriteString("\treturn &txStore\n")
+
+	body := &strings.Builder{}
+	body.WriteString("\tif _, ok := " + v + "." + beginDatabase + ".(" + s.TxDatabaseName() + "); ok {\n")
+	body.WriteString("\t\treturn fn(" + v + ")\n")
+	body.WriteString("\t}\n")
+	body.WriteString("\ttx, err := " + v + "." + beginDatabase + ".#BEGIN()\n")
+	body.WriteString("\tif err != nil {\n")
+	body.WriteString("\t\treturn errors.Trace(err)\n")
+	body.WriteString("\t}\n")
+	body.WriteString("\tif err := fn(" + v + ".WithTx(tx)); err != nil {\n")
+	body.WriteString("\t\tif rollbackErr := tx.Rollback(); rollbackErr != nil {\n")
+	body.WriteString("\t\t\treturn errors.Wrap(err, rollbackErr)\n")
+	body.WriteString("\t\t}\n")
+	body.WriteString("\t\treturn errors.Trace(err)\n")
+	body.WriteString("\t}\n")
+	body.WriteString("\treturn errors.Trace(tx.Commit())\n")
+
+	return []*storeMethod{
+		{
+			function: &Function{
+				name:         "WithTx",
+				parameters:   []*FunctionParameter{{name: "tx", _type: "database.Transaction"}},
+				returnValues: []*FunctionReturnValue{{_type: "Store"}},
+			},
+			comment: "WithTx returns a copy of the Store of which all the databases, and so all its methods, " +
+				"use the given transaction.",
+			body: withTxBody.String(),
+		},
+		{
+			function: &Function{
+				name:         "RunInTx",
+				parameters:   []*FunctionParameter{{name: "fn", _type: "func(Store) error"}},
+				returnValues: []*FunctionReturnValue{{_type: "error"}},
+			},
+			comment: "RunInTx runs fn in a new transaction that is committed when fn succeeds and rolled back " +
+				"when it fails. A Store that is already in a transaction runs fn in that one.",
+			body:         body.String(),
+			usesDatabase: true,
+		},
+	}
+}
+
+// writeTransactionDatabase writes the database the WithTx copies of the store use, which runs the
+// queries in the transaction and refuses to begin nested ones. It's written for every store that
+// can begin transactions, as they all have WithTx copies.
+func (s *Store) writeTransactionDatabase(output io.StringWriter) {
+	if len(s.transactionMethods()) == 0 {
+		return
+	}
+	name := s.TxDatabaseName()
+	output.WriteString("\n")
+	output.WriteString("// " + name + " runs the queries of the " + s.structName +
+		" copies WithTx returns in their transaction.\n")
+	output.WriteString("type " + name + " struct {\n")
+	output.WriteString("\tdatabase.Transaction\n")
+	output.WriteString("}\n")
+	output.WriteString("\n")
+	output.WriteString("// Begin fails as transactions can't be nested.\n")
+	output.WriteString("func (" + name + ") Begin() (database.Transaction, error) {\n")
+	output.WriteString("\treturn nil, errors.New(\"the " + s.structName + " is already in a transaction\")\n")
+	output.WriteString("}\n")
+	output.WriteString("\n")
+	output.WriteString("// BeginTx fails as transactions can't be nested.\n")
+	output.WriteString("func (" + name + ") BeginTx(context.Context, *sql.TxOptions) (database.Transaction, error) {\n")
+	output.WriteString("\treturn nil, errors.New(\"the " + s.structName + " is already in a transaction\")\n")
+	output.WriteString("}\n")
+}
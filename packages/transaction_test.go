@@ -0,0 +1,52 @@
+package packages
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTransactionGeneration(t *testing.T) {
+	tests := []struct {
+		pkg      string
+		expected []string
+		missing  []string
+	}{
+		{"user", []string{
+			"WithTx(tx database.Transaction) Store",
+			"RunInTx(fn func(Store) error) error",
+			"\ttxStore := *s\n" +
+				"\ttxStore.selecterDatabase = usersStoreTxDatabase{Transaction: tx}\n" +
+				"\ttxStore.inserterDatabase = usersStoreTxDatabase{Transaction: tx}\n" +
+				"\ttxStore.updaterDatabase = usersStoreTxDatabase{Transaction: tx}\n" +
+				"\ttxStore.deletorDatabase = usersStoreTxDatabase{Transaction: tx}\n" +
+				"\treturn &txStore\n",
+			"\tif _, ok := s.updaterDatabase.(usersStoreTxDatabase); ok {\n\t\treturn fn(s)\n\t}\n",
+			"\ttx, err := s.updaterDatabase.Begin()\n",
+			"if err := fn(s.WithTx(tx)); err != nil {",
+			"return errors.Wrap(err, rollbackErr)",
+			"return errors.Trace(tx.Commit())",
+			"type usersStoreTxDatabase struct {\n\tdatabase.Transaction\n}",
+			"func (usersStoreTxDatabase) BeginTx(context.Context, *sql.TxOptions) (database.Transaction, error) {",
+			`"database/sql"`,
+		}, nil},
+		{"order", []string{
+			"\tif _, ok := s.inserterDatabase.(ordersStoreTxDatabase); ok {\n",
+			"\ttx, err := s.inserterDatabase.Begin()\n",
+		}, []string{
+			"txStore.updaterDatabase",
+		}},
+	}
+	for _, test := range tests {
+		output := buildTestOutputs(t, loadTestPackage(t, test.pkg))["store_synthesized.go"]
+		for _, expected := range test.expected {
+			if !strings.Contains(output, expected) {
+				t.Errorf("%s store doesn't contain %s\n%s", test.pkg, expected, output)
+			}
+		}
+		for _, missing := range test.missing {
+			if strings.Contains(output, missing) {
+				t.Errorf("%s store shouldn't contain %s", test.pkg, missing)
+			}
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package packages
+
+import (
+	"io"
+	"strconv"
+	"strings"
+)
+
+// TranslationParent returns the entity this translation entity translates, which is the
+// entity in the same package named like the translation without the `Translation` suffix.
+func (e *Entity) TranslationParent() *Entity {
+	if !e.IsTranslation() {
+		return nil
+	}
+	parentName := strings.TrimSuffix(e.name, "Translation")
+	for _, entity := range append([]*Entity{e._package.mainEntity}, e._package.entities...) {
+		if entity != nil && entity.name == parentName {
+			return entity
+		}
+	}
+	return nil
+}
+
+// TranslationForeignKey returns the translation's property that refers to its parent. This is
+// either the property that belongs to the parent or the property named like `<parent>ID`.
+func (e *Entity) TranslationForeignKey() *Property {
+	parent := e.TranslationParent()
+	if parent == nil {
+		return nil
+	}
+	for _, relation := range e.relations {
+		if relation.kind == RelationBelongsTo && relation.target == parent.name {
+			return relation.property
+		}
+	}
+	return e.Property(getFirstLetterLowercase(parent.name) + parent.name[1:] + "ID")
+}
+
+// InsertProperties returns the database properties that are written on inserts. The
+// id and timestamps are left to the database's defaults.
+func (e *Entity) InsertProperties() []*Property {
+	properties := make([]*Property, 0, len(e.properties))
+	for _, property := range e.DatabaseProperties() {
+		switch property.name {
+		case "id", "createdAt", "updatedAt":
+			continue
+		}
+		properties = append(properties, property)
+	}
+	return properties
+}
+
+// translationEntity returns the translation entity of the store's main entity if it has a
+// usable one, meaning it refers to the parent and has the language, field and value properties.
+func (s *Store) translationEntity() *Entity {
+	for _, entity := range s._package.entities {
+		if entity.TranslationParent() != s.mainEntity || entity.TranslationForeignKey() == nil {
+			continue
+		}
+		if entity.Property("language") == nil || entity.Property("field") == nil || entity.Property("value") == nil {
+			continue
+		}
+		return entity
+	}
+	return nil
+}
+
+// translationMethods returns the generated translation methods.
+// nolint:funlen
+func (s *Store) translationMethods() []*storeMethod {
+	translation := s.translationEntity()
+	if translation == nil {
+		return nil
+	}
+	translationType := "*" + translation.Name()
+	alias := translation.TableAlias()
+	table := quoteIdentifier(translation.TableName())
+	column := func(property *Property) string {
+		return quoteIdentifier(property.ColumnName())
+	}
+	foreignKey := translation.TranslationForeignKey()
+	language := translation.Property("language")
+	field := translation.Property("field")
+	value := translation.Property("value")
+	selectQuery := "\"SELECT \"+" + translation.Name() + "SelectColumns+` FROM " + table + " " + alias +
+		" WHERE " + alias + "." + column(foreignKey) + " = $1"
+	if condition := translation.softDeleteCondition(alias); condition != "" {
+		selectQuery += " AND " + condition
+	}
+	idParameter := &FunctionParameter{name: "id", _type: s.mainEntity.idType()}
+	keyParameters := []*FunctionParameter{
+		idParameter, {name: "language", _type: language._type}, {name: "field", _type: field._type},
+	}
+
+	body := &strings.Builder{}
+	body.WriteString("\tresult, ok, err := " + s.VariableName() + ".fetch" + translation.Name() + "#CTX(" +
+		selectQuery + " AND " + alias + "." + column(language) + " = $2 AND " + alias + "." + column(field) +
+		" = $3 LIMIT 1`, false, id, language, field)\n")
+	body.WriteString("\tif len(result) == 1 {\n")
+	body.WriteString("\t\treturn result[0], ok, errors.Trace(err)\n")
+	body.WriteString("\t}\n")
+	body.WriteString("\treturn nil, ok, errors.Trace(err)\n")
+
+	methods := []*storeMethod{
+		{
+			function: &Function{
+				name:       "GetTranslations",
+				parameters: []*FunctionParameter{idParameter},
+				returnValues: []*FunctionReturnValue{
+					{_type: "[]" + translationType}, {_type: "bool"}, {_type: "error"},
+				},
+			},
+			comment: "GetTranslations fetches all translations of the " + s.mainEntity.Name() + " with the given id.",
+			body: "\treturn " + s.VariableName() + ".fetch" + translation.Name() + "#CTX(" + selectQuery +
+				"`, false, id)\n",
+			usesDatabase: true,
+		},
+		{
+			function: &Function{
+				name:       "GetTranslation",
+				parameters: keyParameters,
+				returnValues: []*FunctionReturnValue{
+					{_type: translationType}, {_type: "bool"}, {_type: "error"},
+				},
+			},
+			comment: "GetTranslation fetches the translation of the " + s.mainEntity.Name() +
+				" with the given id for the language and field.",
+			body:         body.String(),
+			usesDatabase: true,
+		},
+	}
+
+	if s.databaseField("inserterDatabase", "updaterDatabase") != "" {
+		columns := make([]string, 0, len(translation.InsertProperties()))
+		placeholders := make([]string, 0, len(translation.InsertProperties()))
+		params := make([]string, 0, len(translation.InsertProperties()))
+		for i, property := range translation.InsertProperties() {
+			columns = append(columns, column(property))
+			placeholders = append(placeholders, "$"+strconv.Itoa(i+1))
+			params = append(params, "translation."+property.name)
+		}
+		updates := []string{column(value) + " = EXCLUDED." + column(value)}
+		if updatedByID := translation.Property("updatedByID"); updatedByID != nil && updatedByID.IsDatabaseField() {
+			updates = append(updates, column(updatedByID)+" = EXCLUDED."+column(updatedByID))
+		}
+		if updatedAt := translation.Property("updatedAt"); updatedAt != nil && updatedAt.IsDatabaseField() {
+			updates = append(updates, column(updatedAt)+" = NOW()")
+		}
+		methods = append(methods, &storeMethod{
+			function: &Function{
+				name:         "UpsertTranslation",
+				parameters:   []*FunctionParameter{{name: "translation", _type: translationType}},
+				returnValues: []*FunctionReturnValue{{_type: "error"}},
+			},
+			comment: "UpsertTranslation inserts the translation or updates the value of the existing one " +
+				"for the same language and field.",
+			body: "\t_, err := #INSERTER.Exec#CTX(`INSERT INTO " + table + " (" + strings.Join(columns, ", ") +
+				") VALUES (" + strings.Join(placeholders, ", ") + ") ON CONFLICT (" + column(foreignKey) + ", " +
+				column(language) + ", " + column(field) + ") DO UPDATE SET " + strings.Join(updates, ", ") + "`, " +
+				strings.Join(params, ", ") + ")\n" +
+				"\treturn errors.Trace(err)\n",
+			usesDatabase: true,
+		})
+	}
+	if s.databaseField("deletorDatabase", "deleterDatabase") != "" {
+		methods = append(methods, &storeMethod{
+			function: &Function{
+				name:         "DeleteTranslation",
+				parameters:   keyParameters,
+				returnValues: []*FunctionReturnValue{{_type: "error"}},
+			},
+			comment: "DeleteTranslation deletes the translation of the " + s.mainEntity.Name() +
+				" with the given id for the language and field.",
+			body: "\t_, err := #DELETOR.Exec#CTX(`DELETE FROM " + table + " WHERE " + column(foreignKey) +
+				" = $1 AND " + column(language) + " = $2 AND " + column(field) + " = $3`, id, language, field)\n" +
+				"\treturn errors.Trace(err)\n",
+			usesDatabase: true,
+		})
+	}
+	return methods
+}
+
+// writeTranslationResolver writes the function that resolves a translated field with language fallbacks.
+func (s *Store) writeTranslationResolver(output io.StringWriter) {
+	translation := s.translationEntity()
+	if translation == nil {
+		return
+	}
+	language := translation.Property("language")
+	field := translation.Property("field")
+	value := translation.Property("value")
+	output.WriteString("\n")
+	output.WriteString("// Resolve" + translation.Name() + " returns the value of the field in the first of the " +
+		"languages that has it,\n")
+	output.WriteString("// so the languages can be given in order of preference with the fallbacks last.\n")
+	output.WriteString("func Resolve" + translation.Name() + "(translations []*" + translation.Name() + ", field " +
+		field._type + ", languages ..." + language._type + ") (" + value._type + ", bool) {\n")
+	output.WriteString("\tfor _, language := range languages {\n")
+	output.WriteString("\t\tfor _, translation := range translations {\n")
+	output.WriteString("\t\t\tif translation." + language.name + " == language && translation." + field.name +
+		" == field {\n")
+	output.WriteString("\t\t\t\treturn translation." + value.name + ", true\n")
+	output.WriteString("\t\t\t}\n")
+	output.WriteString("\t\t}\n")
+	output.WriteString("\t}\n")
+	output.WriteString("\tvar zero " + value._type + "\n")
+	output.WriteString("\treturn zero, false\n")
+	output.WriteString("}\n")
+}
@@ -0,0 +1,38 @@
+package packages
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTranslationGeneration(t *testing.T) {
+	tests := []struct {
+		pkg      string
+		expected []string
+	}{
+		{"user", []string{
+			"GetTranslations(id string) ([]*UserTranslation, bool, error)",
+			"GetTranslation(id string, language uint16, field uint16) (*UserTranslation, bool, error)",
+			"UpsertTranslation(translation *UserTranslation) error",
+			"DeleteTranslation(id string, language uint16, field uint16) error",
+			"ON CONFLICT (\"userID\", \"language\", \"field\") DO UPDATE SET \"value\" = EXCLUDED.\"value\", " +
+				"\"updatedByID\" = EXCLUDED.\"updatedByID\", \"updatedAt\" = NOW()",
+			"func ResolveUserTranslation(translations []*UserTranslation, field uint16, languages ...uint16) " +
+				"(string, bool) {",
+		}},
+		{"order", []string{
+			"GetTranslations(id uint) ([]*OrderTranslation, bool, error)",
+			"GetTranslation(id uint, language uint16, field uint16) (*OrderTranslation, bool, error)",
+			"DeleteTranslation(id uint, language uint16, field uint16) error",
+			"DELETE FROM \"OrderTranslation\" WHERE \"orderID\" = $1 AND \"language\" = $2 AND \"field\" = $3",
+		}},
+	}
+	for _, test := range tests {
+		output := buildTestOutputs(t, loadTestPackage(t, test.pkg))["store_synthesized.go"]
+		for _, expected := range test.expected {
+			if !strings.Contains(output, expected) {
+				t.Errorf("%s store doesn't contain %s\n%s", test.pkg, expected, output)
+			}
+		}
+	}
+}
@@ -0,0 +1,407 @@
+package packages
+
+import (
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// Validation rules of the `@synthesize-validate` annotation.
+const (
+	ValidationRequired = "required"
+	ValidationMin      = "min"
+	ValidationMax      = "max"
+	ValidationEmail    = "email"
+)
+
+const (
+	// ValidationErrorName is the name of the generated error type of a single failed rule.
+	ValidationErrorName = "ValidationError"
+	// ValidationErrorsName is the name of the generated error type Validate returns.
+	ValidationErrorsName = "ValidationErrors"
+)
+
+// numericBounds are the lowest and highest values of the numeric types that can be validated.
+var numericBounds = map[string][2]float64{
+	"int":           {math.MinInt64, math.MaxInt64},
+	"int8":          {math.MinInt8, math.MaxInt8},
+	"int16":         {math.MinInt16, math.MaxInt16},
+	"int32":         {math.MinInt32, math.MaxInt32},
+	"int64":         {math.MinInt64, math.MaxInt64},
+	"uint":          {0, math.MaxUint64},
+	"uint8":         {0, math.MaxUint8},
+	"uint16":        {0, math.MaxUint16},
+	"uint32":        {0, math.MaxUint32},
+	"uint64":        {0, math.MaxUint64},
+	"float32":       {-math.MaxFloat32, math.MaxFloat32},
+	"float64":       {-math.MaxFloat64, math.MaxFloat64},
+	"time.Duration": {math.MinInt64, math.MaxInt64},
+}
+
+// ValidationRule is one of the rules of a property's `@synthesize-validate` comment.
+type ValidationRule struct {
+	name     string
+	argument string
+}
+
+// Name returns the rule's name.
+func (r *ValidationRule) Name() string {
+	return r.name
+}
+
+// Argument returns the rule's argument, like the 255 of `max=255`.
+func (r *ValidationRule) Argument() string {
+	return r.argument
+}
+
+// String returns the rule as it's written in the annotation.
+func (r *ValidationRule) String() string {
+	if r.argument == "" {
+		return r.name
+	}
+	return r.name + "=" + r.argument
+}
+
+// ValidationRules returns the property's validation rules.
+func (p *Property) ValidationRules() []*ValidationRule {
+	return p.validationRules
+}
+
+// isLengthValidated returns if min and max validate the property's length rather than its value.
+func (p *Property) isLengthValidated() bool {
+	return p.IsString() || strings.HasPrefix(p.BaseType(), "[]")
+}
+
+// validationRulesFromProperties parses the `@synthesize-validate rule,rule=argument` comments of the
+// entity's properties.
+// nolint:gocyclo
+func (e *Entity) validationRulesFromProperties() error {
+	for _, property := range e.properties {
+		rules, ok := property.Annotation("validate")
+		if !ok {
+			continue
+		}
+		if rules == "" {
+			return errors.Errorf("`%s.%s` validate needs at least one rule", e.name, property.name)
+		}
+		seen := map[string]bool{}
+		for _, rule := range strings.Split(rules, ",") {
+			parts := strings.SplitN(rule, "=", 2)
+			validationRule := &ValidationRule{name: parts[0]}
+			if len(parts) == 2 {
+				validationRule.argument = parts[1]
+			}
+			if seen[validationRule.name] {
+				return errors.Errorf("`%s.%s` has validation rule `%s` more than once", e.name, property.name,
+					validationRule.name)
+			}
+			seen[validationRule.name] = true
+			switch validationRule.name {
+			case ValidationRequired, ValidationEmail:
+				if validationRule.argument != "" {
+					return errors.Errorf("`%s.%s` validation rule `%s` doesn't take an argument", e.name,
+						property.name, validationRule.name)
+				}
+				if validationRule.name == ValidationEmail && !property.IsString() {
+					return errors.Errorf("`%s.%s` validation rule `email` needs a string", e.name, property.name)
+				}
+			case ValidationMin, ValidationMax:
+				if err := property.checkBoundArgument(validationRule); err != nil {
+					return errors.Annotatef(err, "`%s.%s`", e.name, property.name)
+				}
+			default:
+				return errors.Errorf("`%s.%s` has unknown validation rule `%s`", e.name, property.name,
+					validationRule.name)
+			}
+			property.validationRules = append(property.validationRules, validationRule)
+		}
+	}
+	return nil
+}
+
+// checkBoundArgument checks that the min or max rule's argument fits the property's type.
+func (p *Property) checkBoundArgument(rule *ValidationRule) error {
+	value, err := strconv.ParseFloat(rule.argument, 64)
+	if err != nil {
+		return errors.Errorf("validation rule `%s` needs a numeric argument", rule.name)
+	}
+	if p.isLengthValidated() {
+		if value < 0 || value != math.Trunc(value) {
+			return errors.Errorf("validation rule `%s` needs a non-negative whole length", rule.name)
+		}
+		return nil
+	}
+	bounds, ok := numericBounds[p.BaseType()]
+	if !ok {
+		return errors.Errorf("validation rule `%s` can't validate a `%s`", rule.name, p._type)
+	}
+	if value < bounds[0] || value > bounds[1] {
+		return errors.Errorf("validation rule `%s` is out of range of a `%s`", rule.name, p._type)
+	}
+	if !strings.HasPrefix(p.BaseType(), "float") && value != math.Trunc(value) {
+		return errors.Errorf("validation rule `%s` needs a whole number", rule.name)
+	}
+	return nil
+}
+
+// validationImports returns the imports the entity's Validate method needs.
+func (e *Entity) validationImports() []string {
+	imports := []string{}
+	var hasEmail, hasLength bool
+	for _, property := range e.properties {
+		for _, rule := range property.validationRules {
+			switch {
+			case rule.name == ValidationEmail:
+				hasEmail = true
+			case rule.name != ValidationRequired && property.IsString():
+				hasLength = true
+			}
+		}
+	}
+	if hasEmail {
+		imports = append(imports, "net/mail")
+	}
+	if hasLength {
+		imports = append(imports, "unicode/utf8")
+	}
+	return imports
+}
+
+// validationFailure returns the condition under which the value fails the rule and the error message.
+func (p *Property) validationFailure(rule *ValidationRule, value string) (string, string) {
+	switch rule.name {
+	case ValidationRequired:
+		switch {
+		case p.isLengthValidated() && !p.IsString():
+			return "len(" + value + ") == 0", "is required"
+		case p.IsString():
+			return value + " == \"\"", "is required"
+		case p.BaseType() == "bool":
+			return "!" + value, "is required"
+		case p.IsTime():
+			return value + ".IsZero()", "is required"
+		case p.IsNumeric():
+			return value + " == 0", "is required"
+		}
+		return value + " == " + p.BaseType() + "{}", "is required"
+	case ValidationMin, ValidationMax:
+		operator, bound := " < ", "at least"
+		if rule.name == ValidationMax {
+			operator, bound = " > ", "at most"
+		}
+		switch {
+		case p.IsString():
+			return "utf8.RuneCountInString(" + value + ")" + operator + rule.argument,
+				"must be " + bound + " " + rule.argument + " characters long"
+		case p.IsBytes():
+			return "len(" + value + ")" + operator + rule.argument,
+				"must be " + bound + " " + rule.argument + " bytes long"
+		case p.isLengthValidated():
+			return "len(" + value + ")" + operator + rule.argument,
+				"must have " + bound + " " + rule.argument + " items"
+		}
+		return value + operator + rule.argument, "must be " + bound + " " + rule.argument
+	case ValidationEmail:
+		return "!emailValid", "must be a valid e-mail address"
+	}
+	return "false", ""
+}
+
+// writeValidationTypes writes the error types of failed validations.
+func (e *Entity) writeValidationTypes(output io.StringWriter) {
+	output.WriteString("\n")
+	output.WriteString("// " + ValidationErrorName + " is a validation rule that a property didn't pass.\n")
+	output.WriteString("type " + ValidationErrorName + " struct {\n")
+	output.WriteString("\tField   string\n")
+	output.WriteString("\tRule    string\n")
+	output.WriteString("\tMessage string\n")
+	output.WriteString("}\n")
+	output.WriteString("\n")
+	output.WriteString("func (e *" + ValidationErrorName + ") Error() string {\n")
+	output.WriteString("\treturn e.Field + \" \" + e.Message\n")
+	output.WriteString("}\n")
+	output.WriteString("\n")
+	output.WriteString("// " + ValidationErrorsName + " holds every validation rule an entity didn't pass.\n")
+	output.WriteString("type " + ValidationErrorsName + " []*" + ValidationErrorName + "\n")
+	output.WriteString("\n")
+	output.WriteString("func (e " + ValidationErrorsName + ") Error() string {\n")
+	output.WriteString("\tmessage := \"\"\n")
+	output.WriteString("\tfor i, err := range e {\n")
+	output.WriteString("\t\tif i > 0 {\n")
+	output.WriteString("\t\t\tmessage += \"; \"\n")
+	output.WriteString("\t\t}\n")
+	output.WriteString("\t\tmessage += err.Error()\n")
+	output.WriteString("\t}\n")
+	output.WriteString("\treturn message\n")
+	output.WriteString("}\n")
+}
+
+// writeValidate writes the entity's Validate method, and the error types for the package's main entity.
+func (e *Entity) writeValidate(output io.StringWriter) {
+	if e.IsPrimaryEntity() {
+		e.writeValidationTypes(output)
+	}
+	v := e.VariableName()
+	output.WriteString("\n")
+	output.WriteString("// Validate checks the properties against their validation rules. It returns " +
+		ValidationErrorsName + " with\n")
+	output.WriteString("// every rule that didn't pass.\n")
+	output.WriteString("func (" + v + " *" + e.name + ") Validate() error {\n")
+	output.WriteString("\terrs := " + ValidationErrorsName + "{}\n")
+	for _, property := range e.properties {
+		for _, rule := range property.validationRules {
+			field := v + "." + property.name
+			value := field
+			var guards []string
+			if property.IsPointer() && rule.name != ValidationRequired {
+				guards = append(guards, field+" != nil")
+				value = "*" + field
+			}
+			if rule.name == ValidationRequired && property.IsPointer() {
+				value = field
+			}
+			indent := "\t"
+			if rule.name == ValidationEmail {
+				guards = append(guards, value+" != \"\"")
+				output.WriteString(indent + "if " + strings.Join(guards, " && ") + " {\n")
+				indent += "\t"
+				output.WriteString(indent + "address, err := mail.ParseAddress(" + value + ")\n")
+				output.WriteString(indent + "emailValid := err == nil && address.Address == " + value + "\n")
+				guards = nil
+			}
+			condition, message := property.validationFailure(rule, value)
+			if rule.name == ValidationRequired && property.IsPointer() {
+				condition = field + " == nil"
+			}
+			guards = append(guards, condition)
+			output.WriteString(indent + "if " + strings.Join(guards, " && ") + " {\n")
+			output.WriteString(indent + "\terrs = append(errs, &" + ValidationErrorName + "{Field: \"" + property.name +
+				"\", Rule: \"" + rule.String() + "\", Message: \"" + message + "\"})\n")
+			output.WriteString(indent + "}\n")
+			if rule.name == ValidationEmail {
+				output.WriteString("\t}\n")
+			}
+		}
+	}
+	output.WriteString("\tif len(errs) > 0 {\n")
+	output.WriteString("\t\treturn errs\n")
+	output.WriteString("\t}\n")
+	output.WriteString("\treturn nil\n")
+	output.WriteString("}\n")
+}
+
+// validationTestValues returns Go expressions of a value that passes and one that fails the rule,
+// or empty strings if no value of the property's type can fail it.
+func (p *Property) validationTestValues(rule *ValidationRule) (string, string) {
+	switch rule.name {
+	case ValidationRequired:
+		valid := sampleValue(p)
+		if valid == "#FAULT" {
+			return "", ""
+		}
+		return valid, ""
+	case ValidationEmail:
+		return `"test@example.com"`, `"invalid"`
+	}
+	argument, _ := strconv.ParseFloat(rule.argument, 64)
+	invalid := argument - 1
+	if rule.name == ValidationMax {
+		invalid = argument + 1
+	}
+	if p.isLengthValidated() {
+		if invalid < 0 {
+			return "", ""
+		}
+		if p.IsString() {
+			return `strings.Repeat("a", ` + rule.argument + `)`,
+				`strings.Repeat("a", ` + strconv.FormatFloat(invalid, 'f', -1, 64) + `)`
+		}
+		if p.IsBytes() {
+			return `make([]byte, ` + rule.argument + `)`, `make([]byte, ` + strconv.FormatFloat(invalid, 'f', -1, 64) + `)`
+		}
+		return "", ""
+	}
+	bounds := numericBounds[p.BaseType()]
+	if invalid < bounds[0] || invalid > bounds[1] {
+		return "", ""
+	}
+	return p.BaseType() + "(" + rule.argument + ")", p.BaseType() + "(" + strconv.FormatFloat(invalid, 'f', -1, 64) + ")"
+}
+
+// validationTestUsesStrings returns if the validation tests build strings of a certain length.
+func (e *Entity) validationTestUsesStrings() bool {
+	for _, property := range e.properties {
+		for _, rule := range property.validationRules {
+			if valid, _ := property.validationTestValues(rule); strings.HasPrefix(valid, "strings.") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// writeValidationTests writes a test per validation rule that a valid value passes it and an
+// invalid one doesn't.
+func (e *Entity) writeValidationTests(output io.StringWriter) {
+	v := e.TestVariableName()
+	hasRules := false
+	for _, property := range e.properties {
+		for _, rule := range property.validationRules {
+			valid, invalid := property.validationTestValues(rule)
+			if valid == "" {
+				continue
+			}
+			hasRules = true
+			output.WriteString("func Test" + e.name + "Validate" + property.GetterName() + strings.Title(rule.name) +
+				"(t *testing.T) {\n")
+			output.WriteString("\t" + v + " := " + e.PackageName() + "." + e.PublicNewFunctionName() + "()\n")
+			writeSet := func(value string) {
+				if property.IsPointer() {
+					output.WriteString("\t{\n")
+					output.WriteString("\t\tvalue := " + value + "\n")
+					output.WriteString("\t\t" + v + "." + property.SetterName() + "(&value)\n")
+					output.WriteString("\t}\n")
+					return
+				}
+				output.WriteString("\t" + v + "." + property.SetterName() + "(" + value + ")\n")
+			}
+			writeSet(valid)
+			output.WriteString("\tif has" + e.name + "ValidationError(" + v + ".Validate(), \"" + property.name + "\", \"" +
+				rule.String() + "\") {\n")
+			output.WriteString("\t\tt.Fatal(\"Valid " + property.name + " shouldn't fail " + rule.String() + "\")\n")
+			output.WriteString("\t}\n")
+			if invalid == "" {
+				if property.IsPointer() {
+					output.WriteString("\t" + v + "." + property.SetterName() + "(nil)\n")
+				} else {
+					output.WriteString("\t" + v + " = " + e.PackageName() + "." + e.PublicNewFunctionName() + "()\n")
+				}
+			} else {
+				writeSet(invalid)
+			}
+			output.WriteString("\tif !has" + e.name + "ValidationError(" + v + ".Validate(), \"" + property.name + "\", \"" +
+				rule.String() + "\") {\n")
+			output.WriteString("\t\tt.Fatal(\"Invalid " + property.name + " should fail " + rule.String() + "\")\n")
+			output.WriteString("\t}\n")
+			output.WriteString("}\n\n")
+		}
+	}
+	if !hasRules {
+		return
+	}
+	output.WriteString("func has" + e.name + "ValidationError(err error, field string, rule string) bool {\n")
+	output.WriteString("\terrs, ok := err.(" + e.PackageName() + "." + ValidationErrorsName + ")\n")
+	output.WriteString("\tif !ok {\n")
+	output.WriteString("\t\treturn false\n")
+	output.WriteString("\t}\n")
+	output.WriteString("\tfor _, err := range errs {\n")
+	output.WriteString("\t\tif err.Field == field && err.Rule == rule {\n")
+	output.WriteString("\t\t\treturn true\n")
+	output.WriteString("\t\t}\n")
+	output.WriteString("\t}\n")
+	output.WriteString("\treturn false\n")
+	output.WriteString("}\n\n")
+}
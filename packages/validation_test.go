@@ -0,0 +1,55 @@
+package packages
+
+import (
+	"testing"
+)
+
+func TestValidationRulesFromProperties(t *testing.T) {
+	tests := []struct {
+		_type    string
+		comment  string
+		expected []string
+		fails    bool
+	}{
+		{"string", "", nil, false},
+		{"string", "@synthesize-validate required,max=255,email", []string{"required", "max=255", "email"}, false},
+		{"*string", "Comment. @synthesize-validate required,min=3", []string{"required", "min=3"}, false},
+		{"[]byte", "@synthesize-validate max=4", []string{"max=4"}, false},
+		{"uint16", "@synthesize-validate min=1,max=10", []string{"min=1", "max=10"}, false},
+		{"float64", "@synthesize-validate min=-0.5", []string{"min=-0.5"}, false},
+		{"string", "@synthesize-validate", nil, true},
+		{"string", "@synthesize-validate required,required", nil, true},
+		{"string", "@synthesize-validate required=true", nil, true},
+		{"string", "@synthesize-validate unique", nil, true},
+		{"int", "@synthesize-validate email", nil, true},
+		{"string", "@synthesize-validate max=ten", nil, true},
+		{"string", "@synthesize-validate min=-1", nil, true},
+		{"string", "@synthesize-validate max=2.5", nil, true},
+		{"uint8", "@synthesize-validate max=256", nil, true},
+		{"uint", "@synthesize-validate min=-1", nil, true},
+		{"int", "@synthesize-validate min=1.5", nil, true},
+		{"bool", "@synthesize-validate min=1", nil, true},
+	}
+	for _, test := range tests {
+		property := &Property{name: "field", _type: test._type, comment: test.comment}
+		entity := &Entity{name: "Entity", properties: []*Property{property}}
+		err := entity.validationRulesFromProperties()
+		if (err != nil) != test.fails {
+			t.Errorf("%s `%s` error = %v, expected failure %t", test._type, test.comment, err, test.fails)
+			continue
+		}
+		if test.fails {
+			continue
+		}
+		rules := property.ValidationRules()
+		if len(rules) != len(test.expected) {
+			t.Errorf("%s `%s` has rules %v, expected %v", test._type, test.comment, rules, test.expected)
+			continue
+		}
+		for i, rule := range rules {
+			if rule.String() != test.expected[i] {
+				t.Errorf("%s `%s` rule %d = %s, expected %s", test._type, test.comment, i, rule, test.expected[i])
+			}
+		}
+	}
+}
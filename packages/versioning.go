@@ -0,0 +1,64 @@
+package packages
+
+import (
+	"io"
+)
+
+// VersionProperty returns the property used for optimistic locking. That is the property with a
+// `@synthesize-version` comment or otherwise the `version` property, as long as it's an integer.
+func (e *Entity) VersionProperty() *Property {
+	var version *Property
+	for _, property := range e.DatabaseProperties() {
+		if _, ok := property.Annotation("version"); ok {
+			version = property
+			break
+		}
+	}
+	if version == nil {
+		version = e.Property("version")
+	}
+	if version == nil || !version.IsDatabaseField() {
+		return nil
+	}
+	switch version._type {
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+		return version
+	}
+	return nil
+}
+
+// VersionConflictErrorName returns the name of the error type for failed optimistic locks.
+func (s *Store) VersionConflictErrorName() string {
+	return s.mainEntity.Name() + "VersionConflictError"
+}
+
+// versionConflictIDType returns the type of the version conflict error's ID, which is that of the id property.
+func (s *Store) versionConflictIDType() string {
+	if id := s.mainEntity.Property("id"); id != nil {
+		return id._type
+	}
+	return "string"
+}
+
+// writeVersionConflictError writes the error type updates return when the version didn't match.
+func (s *Store) writeVersionConflictError(output io.StringWriter) {
+	if s.mainEntity.VersionProperty() == nil {
+		return
+	}
+	name := s.VersionConflictErrorName()
+	output.WriteString("\n")
+	output.WriteString("// " + name + " is returned when an update finds that the " + s.mainEntity.Name() +
+		" was changed since it was fetched.\n")
+	output.WriteString("type " + name + " struct {\n")
+	output.WriteString("\t// ID of the conflicting entity. It's nil for batch updates as those can't tell which one it was.\n")
+	output.WriteString("\tID *" + s.versionConflictIDType() + "\n")
+	output.WriteString("}\n")
+	output.WriteString("\n")
+	output.WriteString("func (e *" + name + ") Error() string {\n")
+	output.WriteString("\tif e.ID == nil {\n")
+	output.WriteString("\t\treturn \"version conflict updating " + s.mainEntity.Name() + " entities\"\n")
+	output.WriteString("\t}\n")
+	output.WriteString("\treturn \"version conflict updating " + s.mainEntity.Name() + " \" + " +
+		stringFormat("*e.ID", s.versionConflictIDType()) + "\n")
+	output.WriteString("}\n")
+}
@@ -0,0 +1,499 @@
+package protobuf
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/espal-digital-development/espal-store-synthesizer/packages"
+	"github.com/espal-digital-development/system/permissions"
+	"github.com/juju/errors"
+)
+
+const (
+	// BuildTag is the build tag of the converters, as they need the Go code protoc generates from the .proto files.
+	BuildTag = "proto"
+	// fieldNumbersFile is the file in every package's proto directory that keeps the field numbers between runs.
+	fieldNumbersFile = "field_numbers.json"
+	// corePath is the import path of the espal-core root the proto directory is in.
+	corePath = "github.com/espal-digital-development/espal-core"
+
+	timestampImport = "google.golang.org/protobuf/types/known/timestamppb"
+	durationImport  = "google.golang.org/protobuf/types/known/durationpb"
+)
+
+// Protobuf package object.
+type Protobuf struct {
+	path string
+	// importPath is the import path of the path.
+	importPath string
+}
+
+// fieldNumbers are the field numbers of a package's messages by message and field name.
+type fieldNumbers map[string]map[string]int
+
+// field is a message field of an entity property.
+type field struct {
+	property *packages.Property
+	name     string
+	number   int
+	protoTyp string
+	repeated bool
+	child    *packages.Entity
+}
+
+// Build writes a .proto file per package with a message per entity, and converters between
+// the entities and the messages into the store packages.
+func (p *Protobuf) Build(pkgs []*packages.Package) error {
+	for _, pkg := range pkgs {
+		if err := p.buildPackage(pkg); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// GoPackageName returns the name of the Go package protoc generates for the store package.
+func GoPackageName(pkg *packages.Package) string {
+	return pkg.Name() + "pb"
+}
+
+func (p *Protobuf) buildPackage(pkg *packages.Package) error {
+	directory := p.path + "/" + GoPackageName(pkg)
+	if err := os.MkdirAll(directory, permissions.UserReadWriteExecute); err != nil {
+		return errors.Trace(err)
+	}
+	numbers, err := readFieldNumbers(directory + "/" + fieldNumbersFile)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	messages := map[*packages.Entity][]*field{}
+	for _, entity := range pkg.AllEntities() {
+		messages[entity] = numbers.assign(entity, messageFields(entity))
+	}
+
+	if err := ioutil.WriteFile(directory+"/"+pkg.Name()+".proto", p.protoFile(pkg, messages, numbers),
+		permissions.UserReadWrite); err != nil {
+		return errors.Trace(err)
+	}
+	numbersData, err := json.MarshalIndent(numbers, "", "  ")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := ioutil.WriteFile(directory+"/"+fieldNumbersFile, append(numbersData, '\n'),
+		permissions.UserReadWrite); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(ioutil.WriteFile(pkg.Path()+"/proto_synthesized.go", p.converters(pkg, messages),
+		permissions.UserReadWrite))
+}
+
+func readFieldNumbers(path string) (fieldNumbers, error) {
+	numbers := fieldNumbers{}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return numbers, nil
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := json.Unmarshal(data, &numbers); err != nil {
+		return nil, errors.Annotatef(err, "`%s`", path)
+	}
+	return numbers, nil
+}
+
+// assign gives the fields their kept numbers and new fields the numbers following the highest
+// number the message ever used, so numbers of removed fields aren't reused.
+func (n fieldNumbers) assign(entity *packages.Entity, fields []*field) []*field {
+	numbers, ok := n[entity.Name()]
+	if !ok {
+		numbers = map[string]int{}
+		n[entity.Name()] = numbers
+	}
+	highest := 0
+	for _, number := range numbers {
+		if number > highest {
+			highest = number
+		}
+	}
+	for _, field := range fields {
+		if number, ok := numbers[field.name]; ok {
+			field.number = number
+			continue
+		}
+		highest++
+		field.number = highest
+		numbers[field.name] = highest
+	}
+	return fields
+}
+
+// reserved returns the names and numbers of the message's removed fields.
+func (n fieldNumbers) reserved(entity *packages.Entity, fields []*field) ([]string, []int) {
+	current := map[string]bool{}
+	for _, field := range fields {
+		current[field.name] = true
+	}
+	names := []string{}
+	numbers := []int{}
+	for name, number := range n[entity.Name()] {
+		if !current[name] {
+			names = append(names, name)
+			numbers = append(numbers, number)
+		}
+	}
+	sort.Strings(names)
+	sort.Ints(numbers)
+	return names, numbers
+}
+
+// messageFields returns the fields of the entity's message. The `@synthesize-no-db-field` properties
+// are left out, apart from has-many relations.
+func messageFields(entity *packages.Entity) []*field {
+	fields := []*field{}
+	for _, property := range entity.Properties() {
+		field := &field{
+			property: property,
+			name:     packages.NamingSnakeCase.ColumnName(strings.TrimPrefix(property.Name(), "_")),
+		}
+		for _, relation := range entity.Relations() {
+			if relation.Property() == property && relation.Kind() == packages.RelationHasMany {
+				field.child = relation.TargetEntity()
+			}
+		}
+		switch {
+		case field.child != nil:
+			field.protoTyp = field.child.Name()
+			field.repeated = true
+		case !property.IsDatabaseField():
+			continue
+		default:
+			field.protoTyp = scalarType(property.BaseType())
+			if field.protoTyp == "" && strings.HasPrefix(property.BaseType(), "[]") {
+				field.protoTyp = scalarType(strings.TrimPrefix(property.BaseType(), "[]"))
+				field.repeated = true
+			}
+		}
+		if field.protoTyp == "" {
+			continue
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// scalarType returns the protocol buffers type of the Go type or an empty string if it has none.
+func scalarType(goType string) string {
+	switch goType {
+	case "string", "bool", "int32", "int64", "uint32", "uint64":
+		return goType
+	case "int8", "int16":
+		return "int32"
+	case "int":
+		return "int64"
+	case "uint8", "uint16":
+		return "uint32"
+	case "uint":
+		return "uint64"
+	case "float32":
+		return "float"
+	case "float64":
+		return "double"
+	case "[]byte":
+		return "bytes"
+	case "time.Time":
+		return "google.protobuf.Timestamp"
+	case "time.Duration":
+		return "google.protobuf.Duration"
+	}
+	return ""
+}
+
+// isMessage returns if the field's type is a message, which are nil-able without being optional.
+func (f *field) isMessage() bool {
+	return f.child != nil || strings.HasPrefix(f.protoTyp, "google.")
+}
+
+// goName returns the name protoc gives the field in Go.
+func (f *field) goName() string {
+	parts := strings.Split(f.name, "_")
+	for i := range parts {
+		parts[i] = strings.Title(parts[i])
+	}
+	return strings.Join(parts, "")
+}
+
+func (p *Protobuf) protoFile(pkg *packages.Package, messages map[*packages.Entity][]*field,
+	numbers fieldNumbers) []byte {
+	output := &strings.Builder{}
+	output.WriteString("// Code generated by espal-store-synthesizer. DO NOT EDIT.\n")
+	output.WriteString("syntax = \"proto3\";\n\n")
+	output.WriteString("package stores." + pkg.Name() + ";\n\n")
+	imports := map[string]bool{}
+	for _, fields := range messages {
+		for _, field := range fields {
+			switch field.protoTyp {
+			case "google.protobuf.Timestamp":
+				imports["google/protobuf/timestamp.proto"] = true
+			case "google.protobuf.Duration":
+				imports["google/protobuf/duration.proto"] = true
+			}
+		}
+	}
+	if imports["google/protobuf/duration.proto"] {
+		output.WriteString("import \"google/protobuf/duration.proto\";\n")
+	}
+	if imports["google/protobuf/timestamp.proto"] {
+		output.WriteString("import \"google/protobuf/timestamp.proto\";\n")
+	}
+	if len(imports) > 0 {
+		output.WriteString("\n")
+	}
+	output.WriteString("option go_package = \"" + p.goImportPath(pkg) + "\";\n")
+
+	for _, entity := range pkg.AllEntities() {
+		fields := messages[entity]
+		output.WriteString("\n")
+		output.WriteString("// " + entity.Name() + " of the " + pkg.Name() + " store.\n")
+		output.WriteString("message " + entity.Name() + " {\n")
+		names, reservedNumbers := numbers.reserved(entity, fields)
+		if len(reservedNumbers) > 0 {
+			numberTexts := make([]string, len(reservedNumbers))
+			for i, number := range reservedNumbers {
+				numberTexts[i] = strconv.Itoa(number)
+			}
+			output.WriteString("  reserved " + strings.Join(numberTexts, ", ") + ";\n")
+			output.WriteString("  reserved \"" + strings.Join(names, "\", \"") + "\";\n")
+		}
+		for _, field := range fields {
+			if description := field.property.Description(); description != "" {
+				output.WriteString("  // " + description + "\n")
+			}
+			output.WriteString("  ")
+			switch {
+			case field.repeated:
+				output.WriteString("repeated ")
+			case field.property.IsPointer() && !field.isMessage():
+				output.WriteString("optional ")
+			}
+			output.WriteString(field.protoTyp + " " + field.name + " = " + strconv.Itoa(field.number) + ";\n")
+		}
+		output.WriteString("}\n")
+	}
+	return []byte(output.String())
+}
+
+// goImportPath returns the import path of the Go package protoc generates for the store package.
+func (p *Protobuf) goImportPath(pkg *packages.Package) string {
+	return p.importPath + "/" + GoPackageName(pkg)
+}
+
+// converters writes the To<Entity>Proto and From<Entity>Proto functions of the package's entities.
+// nolint:funlen
+func (p *Protobuf) converters(pkg *packages.Package, messages map[*packages.Entity][]*field) []byte {
+	pb := GoPackageName(pkg)
+	body := &strings.Builder{}
+	imports := map[string]bool{}
+	for _, entity := range pkg.AllEntities() {
+		v := entity.VariableName()
+		message := pb + "." + entity.Name()
+
+		body.WriteString("\n")
+		body.WriteString("// To" + entity.Name() + "Proto converts the " + entity.Name() +
+			" to its protocol buffers message.\n")
+		body.WriteString("func To" + entity.Name() + "Proto(" + v + " *" + entity.Name() + ") *" + message + " {\n")
+		body.WriteString("\tif " + v + " == nil {\n")
+		body.WriteString("\t\treturn nil\n")
+		body.WriteString("\t}\n")
+		body.WriteString("\tmessage := &" + message + "{}\n")
+		for _, field := range messages[entity] {
+			getter := v + "." + field.property.GetterName() + "()"
+			property := field.property
+			switch {
+			case field.child != nil:
+				body.WriteString("\tfor _, child := range " + getter + " {\n")
+				body.WriteString("\t\tmessage." + field.goName() + " = append(message." + field.goName() + ", To" +
+					field.child.Name() + "Proto(child))\n")
+				body.WriteString("\t}\n")
+			case property.IsPointer():
+				imports[field.goImport()] = true
+				body.WriteString("\tif value := " + getter + "; value != nil {\n")
+				if field.isMessage() {
+					body.WriteString("\t\tmessage." + field.goName() + " = " + field.toProto("*value") + "\n")
+				} else {
+					body.WriteString("\t\tconverted := " + field.toProto("*value") + "\n")
+					body.WriteString("\t\tmessage." + field.goName() + " = &converted\n")
+				}
+				body.WriteString("\t}\n")
+			case field.repeated && !property.IsBytes():
+				body.WriteString("\tfor _, value := range " + getter + " {\n")
+				body.WriteString("\t\tmessage." + field.goName() + " = append(message." + field.goName() + ", " +
+					field.toProto("value") + ")\n")
+				body.WriteString("\t}\n")
+			default:
+				imports[field.goImport()] = true
+				body.WriteString("\tmessage." + field.goName() + " = " + field.toProto(getter) + "\n")
+			}
+		}
+		body.WriteString("\treturn message\n")
+		body.WriteString("}\n")
+
+		body.WriteString("\n")
+		body.WriteString("// From" + entity.Name() + "Proto converts the protocol buffers message to a " +
+			entity.Name() + ".\n")
+		body.WriteString("func From" + entity.Name() + "Proto(message *" + message + ") *" + entity.Name() + " {\n")
+		body.WriteString("\tif message == nil {\n")
+		body.WriteString("\t\treturn nil\n")
+		body.WriteString("\t}\n")
+		body.WriteString("\t" + v + " := new" + entity.Name() + "()\n")
+		for _, field := range messages[entity] {
+			property := field.property
+			set := func(value string) string {
+				if property.Name() == "id" {
+					return v + ".id = " + value
+				}
+				return v + "." + property.SetterName() + "(" + value + ")"
+			}
+			source := "message." + field.goName()
+			switch {
+			case field.child != nil:
+				body.WriteString("\tif " + source + " != nil {\n")
+				body.WriteString("\t\tchildren := make(" + property.Type() + ", len(" + source + "))\n")
+				body.WriteString("\t\tfor i, child := range " + source + " {\n")
+				body.WriteString("\t\t\tchildren[i] = From" + field.child.Name() + "Proto(child)\n")
+				body.WriteString("\t\t}\n")
+				body.WriteString("\t\t" + set("children") + "\n")
+				body.WriteString("\t}\n")
+			case field.isMessage() || property.IsPointer():
+				body.WriteString("\tif " + source + " != nil {\n")
+				value := source
+				if !field.isMessage() {
+					value = "*" + source
+				}
+				if property.IsPointer() {
+					body.WriteString("\t\tconverted := " + field.fromProto(value) + "\n")
+					body.WriteString("\t\t" + set("&converted") + "\n")
+				} else {
+					body.WriteString("\t\t" + set(field.fromProto(value)) + "\n")
+				}
+				body.WriteString("\t}\n")
+			case field.repeated && !property.IsBytes():
+				body.WriteString("\tif " + source + " != nil {\n")
+				body.WriteString("\t\tvalues := make(" + property.Type() + ", len(" + source + "))\n")
+				body.WriteString("\t\tfor i, value := range " + source + " {\n")
+				body.WriteString("\t\t\tvalues[i] = " + field.fromProto("value") + "\n")
+				body.WriteString("\t\t}\n")
+				body.WriteString("\t\t" + set("values") + "\n")
+				body.WriteString("\t}\n")
+			default:
+				body.WriteString("\t" + set(field.fromProto(source)) + "\n")
+			}
+		}
+		if entity.TracksChanges() {
+			body.WriteString("\t" + v + ".ResetChanges()\n")
+		}
+		body.WriteString("\treturn " + v + "\n")
+		body.WriteString("}\n")
+	}
+
+	output := &strings.Builder{}
+	output.WriteString("// Code generated by espal-store-synthesizer. DO NOT EDIT.\n")
+	output.WriteString("//go:build " + BuildTag + "\n")
+	output.WriteString("// +build " + BuildTag + "\n\n")
+	output.WriteString("package " + pkg.Name() + "\n\n")
+	output.WriteString("import (\n")
+	if imports[durationImport] {
+		output.WriteString("\t\"" + durationImport + "\"\n")
+	}
+	if imports[timestampImport] {
+		output.WriteString("\t\"" + timestampImport + "\"\n")
+	}
+	output.WriteString("\n\t\"" + p.goImportPath(pkg) + "\"\n")
+	output.WriteString(")\n")
+	output.WriteString(body.String())
+	return []byte(output.String())
+}
+
+// goImport returns the import the field's conversions need, if any.
+func (f *field) goImport() string {
+	switch f.protoTyp {
+	case "google.protobuf.Timestamp":
+		return timestampImport
+	case "google.protobuf.Duration":
+		return durationImport
+	}
+	return ""
+}
+
+// toProto returns the expression that converts the Go value to the field's type.
+func (f *field) toProto(value string) string {
+	base := strings.TrimPrefix(f.property.BaseType(), "[]")
+	switch {
+	case f.protoTyp == "google.protobuf.Timestamp":
+		return "timestamppb.New(" + value + ")"
+	case f.protoTyp == "google.protobuf.Duration":
+		return "durationpb.New(" + value + ")"
+	case f.property.IsBytes() || base == f.goProtoType():
+		return value
+	}
+	return f.goProtoType() + "(" + value + ")"
+}
+
+// fromProto returns the expression that converts the field's value to the property's Go type.
+func (f *field) fromProto(value string) string {
+	base := strings.TrimPrefix(f.property.BaseType(), "[]")
+	switch {
+	case f.protoTyp == "google.protobuf.Timestamp":
+		return value + ".AsTime()"
+	case f.protoTyp == "google.protobuf.Duration":
+		return value + ".AsDuration()"
+	case f.property.IsBytes() || base == f.goProtoType():
+		return value
+	}
+	return base + "(" + value + ")"
+}
+
+// goProtoType returns the Go type protoc generates for the field's scalar type.
+func (f *field) goProtoType() string {
+	switch f.protoTyp {
+	case "float":
+		return "float32"
+	case "double":
+		return "float64"
+	case "bytes":
+		return "[]byte"
+	}
+	return f.protoTyp
+}
+
+// New returns a new instance of Protobuf that writes the .proto files to the directory, which
+// has to be inside the espal-core root so protoc's Go packages can be imported from there.
+func New(path string, rootPath string) (*Protobuf, error) {
+	if path == "" {
+		return nil, errors.New("the .proto files need a directory")
+	}
+	absolutePath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	relativePath, err := filepath.Rel(rootPath, absolutePath)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if relativePath == ".." || strings.HasPrefix(relativePath, "../") {
+		return nil, errors.Errorf("the .proto directory `%s` is outside of `%s`", path, rootPath)
+	}
+	importPath := corePath
+	if relativePath != "." {
+		importPath += "/" + filepath.ToSlash(relativePath)
+	}
+	return &Protobuf{
+		path:       absolutePath,
+		importPath: importPath,
+	}, nil
+}
@@ -0,0 +1,106 @@
+package protobuf
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/espal-digital-development/espal-store-synthesizer/packages"
+	"github.com/espal-digital-development/system/permissions"
+	"github.com/juju/errors"
+)
+
+// copyTestStore copies the testdata store into a temporary directory, as the converters are
+// written into the store package.
+func copyTestStore(t *testing.T, name string) string {
+	t.Helper()
+	directory := t.TempDir() + "/stores/" + name
+	if err := os.MkdirAll(directory, permissions.UserReadWriteExecute); err != nil {
+		t.Fatal(err)
+	}
+	files, err := filepath.Glob("../testdata/stores/" + name + "/*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(directory+"/"+filepath.Base(file), data, permissions.UserReadWrite); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return directory
+}
+
+func buildProto(t *testing.T, storePath string, protoPath string) string {
+	t.Helper()
+	pkg := packages.New(&packages.Config{NamingStrategy: packages.NamingAsIs})
+	if err := pkg.BuildMetaData(storePath); err != nil {
+		t.Fatal(errors.ErrorStack(err))
+	}
+	protobuf, err := New(protoPath, filepath.Dir(protoPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := protobuf.Build([]*packages.Package{pkg}); err != nil {
+		t.Fatal(errors.ErrorStack(err))
+	}
+	output, err := ioutil.ReadFile(protoPath + "/userpb/user.proto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(output)
+}
+
+func TestBuildKeepsFieldNumbers(t *testing.T) {
+	storePath := copyTestStore(t, "user")
+	protoPath := t.TempDir() + "/proto"
+	output := buildProto(t, storePath, protoPath)
+	for _, expected := range []string{
+		"package stores.user;\n",
+		"option go_package = \"" + corePath + "/proto/userpb\";\n",
+		"  optional string first_name = 13;\n",
+		"  google.protobuf.Timestamp date_of_birth = 14;\n",
+		"  uint32 priority = 16;\n",
+		"  repeated Address addresses = 17;\n",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %s in\n%s", expected, output)
+		}
+	}
+	if strings.Contains(output, "reserved") {
+		t.Errorf("A first build shouldn't reserve fields\n%s", output)
+	}
+
+	// Drop firstName and add a nickname
+	userPath := storePath + "/user.go"
+	data, err := ioutil.ReadFile(userPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	source := strings.Replace(string(data), "\tfirstName          *string\n", "\tnickname           *string\n", 1)
+	if err := ioutil.WriteFile(userPath, []byte(source), permissions.UserReadWrite); err != nil {
+		t.Fatal(err)
+	}
+	output = buildProto(t, storePath, protoPath)
+	for _, expected := range []string{
+		"  reserved 13;\n  reserved \"first_name\";\n",
+		"  google.protobuf.Timestamp date_of_birth = 14;\n",
+		"  optional string nickname = 18;\n",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %s after dropping firstName in\n%s", expected, output)
+		}
+	}
+
+	// The dropped number stays reserved in later builds
+	output = buildProto(t, storePath, protoPath)
+	user := output[strings.Index(output, "message User {"):strings.Index(output, "message Address {")]
+	if !strings.Contains(user, "  reserved 13;\n") || strings.Contains(user, "= 13;") {
+		t.Errorf("Expected 13 to stay reserved in\n%s", user)
+	}
+}
@@ -0,0 +1,407 @@
+package seed
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/espal-digital-development/espal-store-synthesizer/packages"
+	"github.com/juju/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// Output formats of the seed command.
+const (
+	FormatSQL = "sql"
+	FormatGo  = "go"
+)
+
+// timeLayouts are the layouts time values in seed files can be written in.
+var timeLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02"}
+
+// Seed turns seed files into SQL INSERT statements or Go code that builds the entities.
+type Seed struct {
+	entities map[string][]*seedEntity
+}
+
+// seedEntity is an entity that can be seeded along with its package.
+type seedEntity struct {
+	entity *packages.Entity
+	pkg    *packages.Package
+}
+
+// record is one entity of the seed file with its properties in the order they were written.
+type record struct {
+	entity *seedEntity
+	index  int
+	values yaml.MapSlice
+}
+
+// value is a converted seed value.
+type value struct {
+	property *packages.Property
+	sql      string
+	goCode   string
+}
+
+// Build converts the YAML or JSON seed file to the given format. The seed file is keyed by entity
+// name, or `package.Entity` if the name is ambiguous, with a list of property values per entity.
+func (s *Seed) Build(data []byte, outputFormat string, packageName string) ([]byte, error) {
+	records, err := s.parse(data)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	switch outputFormat {
+	case FormatSQL:
+		return s.buildSQL(records)
+	case FormatGo:
+		return s.buildGo(records, packageName)
+	}
+	return nil, errors.Errorf("unknown seed format `%s`, expected %s or %s", outputFormat, FormatSQL, FormatGo)
+}
+
+func (s *Seed) parse(data []byte) ([]*record, error) {
+	document := yaml.MapSlice{}
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, errors.Trace(err)
+	}
+	records := []*record{}
+	for _, item := range document {
+		name := fmt.Sprint(item.Key)
+		entity, err := s.entity(name)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		list, ok := item.Value.([]interface{})
+		if !ok {
+			return nil, errors.Errorf("`%s` must be a list of entities", name)
+		}
+		for i, item := range list {
+			values, ok := item.(yaml.MapSlice)
+			if !ok {
+				return nil, errors.Errorf("`%s[%d]` must be a map of property values", name, i)
+			}
+			records = append(records, &record{entity: entity, index: i, values: values})
+		}
+	}
+	return records, nil
+}
+
+func (s *Seed) entity(name string) (*seedEntity, error) {
+	entities := s.entities[name]
+	switch len(entities) {
+	case 0:
+		return nil, errors.Errorf("unknown entity `%s`", name)
+	case 1:
+		return entities[0], nil
+	}
+	return nil, errors.Errorf("entity `%s` is ambiguous, prefix it with its package", name)
+}
+
+// convert reads the record's values, with properties limited to the database columns if required.
+func (r *record) convert(columnsOnly bool) ([]*value, error) {
+	values := make([]*value, 0, len(r.values))
+	for _, item := range r.values {
+		name := fmt.Sprint(item.Key)
+		path := fmt.Sprintf("%s[%d].%s", r.entity.entity.Name(), r.index, name)
+		property := r.entity.entity.Property(name)
+		if property == nil {
+			return nil, errors.Errorf("`%s` is not a property", path)
+		}
+		if columnsOnly && !isColumn(r.entity.entity, property) {
+			return nil, errors.Errorf("`%s` is not a column", path)
+		}
+		converted, err := convertValue(property, item.Value)
+		if err != nil {
+			return nil, errors.Annotatef(err, "`%s`", path)
+		}
+		values = append(values, converted)
+	}
+	return values, nil
+}
+
+func isColumn(entity *packages.Entity, property *packages.Property) bool {
+	for _, column := range entity.DatabaseProperties() {
+		if column == property {
+			return true
+		}
+	}
+	return false
+}
+
+// convertValue converts the seed file's value to the SQL and Go literals of the property's type.
+// nolint:gocyclo,funlen
+func convertValue(property *packages.Property, raw interface{}) (*value, error) {
+	if raw == nil {
+		if !property.IsPointer() && !property.IsBytes() {
+			return nil, errors.New("can't be null")
+		}
+		return &value{property: property, sql: "NULL", goCode: "nil"}, nil
+	}
+	baseType := property.BaseType()
+	switch baseType {
+	case "string":
+		text, err := scalar(raw)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return &value{property: property, sql: quoteString(text), goCode: strconv.Quote(text)}, nil
+	case "bool":
+		boolean, ok := raw.(bool)
+		if !ok {
+			return nil, errors.Errorf("expected a boolean, got `%v`", raw)
+		}
+		return &value{property: property, sql: strings.ToUpper(strconv.FormatBool(boolean)),
+			goCode: strconv.FormatBool(boolean)}, nil
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+		text, err := scalar(raw)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if strings.HasPrefix(baseType, "u") {
+			_, err = strconv.ParseUint(text, 10, bitSize(baseType))
+		} else {
+			_, err = strconv.ParseInt(text, 10, bitSize(baseType))
+		}
+		if err != nil {
+			return nil, errors.Errorf("expected a %s, got `%v`", baseType, raw)
+		}
+		return &value{property: property, sql: text, goCode: baseType + "(" + text + ")"}, nil
+	case "float32", "float64":
+		text, err := scalar(raw)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		number, err := strconv.ParseFloat(text, bitSize(baseType))
+		if err != nil {
+			return nil, errors.Errorf("expected a %s, got `%v`", baseType, raw)
+		}
+		text = strconv.FormatFloat(number, 'g', -1, bitSize(baseType))
+		return &value{property: property, sql: text, goCode: baseType + "(" + text + ")"}, nil
+	case "time.Time":
+		moment, err := parseTime(raw)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		moment = moment.UTC()
+		return &value{property: property, sql: quoteString(moment.Format(time.RFC3339Nano)),
+			goCode: fmt.Sprintf("time.Date(%d, time.%s, %d, %d, %d, %d, %d, time.UTC)", moment.Year(),
+				moment.Month(), moment.Day(), moment.Hour(), moment.Minute(), moment.Second(), moment.Nanosecond())}, nil
+	case "time.Duration":
+		duration, err := parseDuration(raw)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		nanoseconds := strconv.FormatInt(int64(duration), 10)
+		return &value{property: property, sql: nanoseconds, goCode: "time.Duration(" + nanoseconds + ")"}, nil
+	case "[]byte":
+		text, ok := raw.(string)
+		if !ok {
+			return nil, errors.Errorf("expected a base64 string, got `%v`", raw)
+		}
+		data, err := base64.StdEncoding.DecodeString(text)
+		if err != nil {
+			return nil, errors.Annotate(err, "expected a base64 string")
+		}
+		return &value{property: property, sql: `'\x` + hex.EncodeToString(data) + `'`,
+			goCode: "[]byte(" + strconv.Quote(string(data)) + ")"}, nil
+	}
+	return nil, errors.Errorf("can't seed a `%s`", property.Type())
+}
+
+// scalar returns the text of a string or number.
+func scalar(raw interface{}) (string, error) {
+	switch raw := raw.(type) {
+	case string:
+		return raw, nil
+	case int, int64, uint64, float64:
+		return fmt.Sprint(raw), nil
+	}
+	return "", errors.Errorf("expected a scalar, got `%v`", raw)
+}
+
+func bitSize(numericType string) int {
+	for _, size := range []string{"8", "16", "32", "64"} {
+		if strings.HasSuffix(numericType, size) {
+			size, _ := strconv.Atoi(size)
+			return size
+		}
+	}
+	return 64
+}
+
+func parseTime(raw interface{}) (time.Time, error) {
+	if moment, ok := raw.(time.Time); ok {
+		return moment, nil
+	}
+	if text, ok := raw.(string); ok {
+		for _, layout := range timeLayouts {
+			if moment, err := time.Parse(layout, text); err == nil {
+				return moment, nil
+			}
+		}
+	}
+	return time.Time{}, errors.Errorf("expected a time like `2006-01-02T15:04:05Z`, got `%v`", raw)
+}
+
+func parseDuration(raw interface{}) (time.Duration, error) {
+	switch raw := raw.(type) {
+	case string:
+		duration, err := time.ParseDuration(raw)
+		return duration, errors.Annotate(err, "expected a duration like `1h30m`")
+	case int:
+		return time.Duration(raw), nil
+	}
+	return 0, errors.Errorf("expected a duration like `1h30m` or nanoseconds, got `%v`", raw)
+}
+
+func quoteString(text string) string {
+	return "'" + strings.ReplaceAll(text, "'", "''") + "'"
+}
+
+func quoteIdentifier(name string) string {
+	return `"` + name + `"`
+}
+
+func (s *Seed) buildSQL(records []*record) ([]byte, error) {
+	output := bytes.NewBufferString("-- Code generated by espal-store-synthesizer. DO NOT EDIT.\n")
+	for _, record := range records {
+		values, err := record.convert(true)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		output.WriteString("INSERT INTO " + quoteIdentifier(record.entity.entity.TableName()))
+		if len(values) == 0 {
+			output.WriteString(" DEFAULT VALUES;\n")
+			continue
+		}
+		columns := make([]string, len(values))
+		literals := make([]string, len(values))
+		for i, value := range values {
+			columns[i] = quoteIdentifier(value.property.ColumnName())
+			literals[i] = value.sql
+		}
+		output.WriteString(" (" + strings.Join(columns, ", ") + ") VALUES (" + strings.Join(literals, ", ") + ");\n")
+	}
+	return output.Bytes(), nil
+}
+
+// nolint:funlen
+func (s *Seed) buildGo(records []*record, packageName string) ([]byte, error) {
+	body := &strings.Builder{}
+	imports := map[string]string{}
+	functionNames := map[*seedEntity]string{}
+	order := []*seedEntity{}
+	bodies := map[*seedEntity]*strings.Builder{}
+	for _, record := range records {
+		values, err := record.convert(false)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		entity := record.entity.entity
+		pkg := record.entity.pkg
+		fixtures := pkg.Name() + packages.FixturesPackageName
+		imports[pkg.ImportPath()] = pkg.Name()
+		imports[pkg.ImportPath()+"/"+packages.FixturesPackageName] = fixtures
+
+		if _, ok := bodies[record.entity]; !ok {
+			order = append(order, record.entity)
+			bodies[record.entity] = &strings.Builder{}
+		}
+		entityBody := bodies[record.entity]
+		v := entity.VariableName()
+		entityBody.WriteString("\t\t" + fixtures + "." + entity.FixtureFunctionName() + "(func(" + v + " *" +
+			pkg.Name() + "." + entity.Name() + ") {\n")
+		for _, value := range values {
+			if value.property.Name() == "id" {
+				return nil, errors.Errorf("`%s[%d].id` can't be set from Go, use the %s format instead",
+					entity.Name(), record.index, FormatSQL)
+			}
+			if strings.HasPrefix(value.goCode, "time.") {
+				imports["time"] = "time"
+			}
+			if value.property.IsPointer() && value.goCode != "nil" {
+				entityBody.WriteString("\t\t\t{\n")
+				entityBody.WriteString("\t\t\t\tvalue := " + value.goCode + "\n")
+				entityBody.WriteString("\t\t\t\t" + v + "." + value.property.SetterName() + "(&value)\n")
+				entityBody.WriteString("\t\t\t}\n")
+				continue
+			}
+			entityBody.WriteString("\t\t\t" + v + "." + value.property.SetterName() + "(" + value.goCode + ")\n")
+		}
+		entityBody.WriteString("\t\t}),\n")
+	}
+
+	for _, entity := range order {
+		name := entity.entity.Name() + "Seeds"
+		for _, used := range functionNames {
+			if used == name {
+				name = strings.Title(entity.pkg.Name()) + name
+				break
+			}
+		}
+		functionNames[entity] = name
+		qualifiedName := entity.pkg.Name() + "." + entity.entity.Name()
+		body.WriteString("\n")
+		body.WriteString("// " + name + " returns the seeded " + entity.entity.Name() +
+			" entities on top of their fixtures.\n")
+		body.WriteString("func " + name + "() []*" + qualifiedName + " {\n")
+		body.WriteString("\treturn []*" + qualifiedName + "{\n")
+		body.WriteString(bodies[entity].String())
+		body.WriteString("\t}\n")
+		body.WriteString("}\n")
+	}
+
+	output := bytes.NewBufferString("// Code generated by espal-store-synthesizer. DO NOT EDIT.\n")
+	output.WriteString("package " + packageName + "\n")
+	if len(imports) > 0 {
+		paths := make([]string, 0, len(imports))
+		for path := range imports {
+			paths = append(paths, path)
+		}
+		// Standard library imports go first, in their own group
+		sort.Slice(paths, func(i, j int) bool {
+			iStandard, jStandard := !strings.Contains(paths[i], "."), !strings.Contains(paths[j], ".")
+			if iStandard != jStandard {
+				return iStandard
+			}
+			return paths[i] < paths[j]
+		})
+		output.WriteString("\nimport (\n")
+		for i, path := range paths {
+			if i > 0 && !strings.Contains(paths[i-1], ".") && strings.Contains(path, ".") {
+				output.WriteString("\n")
+			}
+			output.WriteString("\t")
+			if alias := imports[path]; alias != path && !strings.HasSuffix(path, "/"+alias) {
+				output.WriteString(alias + " ")
+			}
+			output.WriteString("\"" + path + "\"\n")
+		}
+		output.WriteString(")\n")
+	}
+	output.WriteString(body.String())
+	formatted, err := format.Source(output.Bytes())
+	return formatted, errors.Trace(err)
+}
+
+// New returns a new instance of Seed for the entities of the packages.
+func New(pkgs []*packages.Package) (*Seed, error) {
+	s := &Seed{
+		entities: map[string][]*seedEntity{},
+	}
+	for _, pkg := range pkgs {
+		for _, entity := range pkg.AllEntities() {
+			seedEntity := &seedEntity{entity: entity, pkg: pkg}
+			s.entities[entity.Name()] = append(s.entities[entity.Name()], seedEntity)
+			s.entities[pkg.Name()+"."+entity.Name()] = append(s.entities[pkg.Name()+"."+entity.Name()], seedEntity)
+		}
+	}
+	return s, nil
+}
@@ -0,0 +1,66 @@
+package seed
+
+import (
+	"testing"
+	"time"
+
+	"github.com/espal-digital-development/espal-store-synthesizer/packages"
+)
+
+func TestConvertValue(t *testing.T) {
+	tests := []struct {
+		_type  string
+		raw    interface{}
+		sql    string
+		goCode string
+		fails  bool
+	}{
+		{"string", "it's", "'it''s'", `"it's"`, false},
+		{"string", 12, "'12'", `"12"`, false},
+		{"*string", nil, "NULL", "nil", false},
+		{"string", nil, "", "", true},
+		{"string", []interface{}{"a"}, "", "", true},
+		{"bool", true, "TRUE", "true", false},
+		{"bool", "yes", "", "", true},
+		{"int", 42, "42", "int(42)", false},
+		{"*int64", -7, "-7", "int64(-7)", false},
+		{"uint8", 255, "255", "uint8(255)", false},
+		{"uint8", 256, "", "", true},
+		{"uint", -1, "", "", true},
+		{"int", "ten", "", "", true},
+		{"float64", 1.5, "1.5", "float64(1.5)", false},
+		{"float32", "0.25", "0.25", "float32(0.25)", false},
+		{"float64", "half", "", "", true},
+		{"time.Time", "2020-01-02", "'2020-01-02T00:00:00Z'", "time.Date(2020, time.January, 2, 0, 0, 0, 0, time.UTC)",
+			false},
+		{"time.Time", time.Date(2020, time.March, 4, 5, 6, 7, 8, time.UTC), "'2020-03-04T05:06:07.000000008Z'",
+			"time.Date(2020, time.March, 4, 5, 6, 7, 8, time.UTC)", false},
+		{"time.Time", "yesterday", "", "", true},
+		{"time.Duration", "1h30m", "5400000000000", "time.Duration(5400000000000)", false},
+		{"time.Duration", 1000, "1000", "time.Duration(1000)", false},
+		{"time.Duration", "long", "", "", true},
+		{"[]byte", "aGk=", `'\x6869'`, `[]byte("hi")`, false},
+		{"[]byte", nil, "NULL", "nil", false},
+		{"[]byte", "not base64!", "", "", true},
+		{"map[string]string", "a", "", "", true},
+	}
+	for _, test := range tests {
+		property := &packages.Property{}
+		property.SetName("field")
+		property.SetType(test._type)
+		converted, err := convertValue(property, test.raw)
+		if (err != nil) != test.fails {
+			t.Errorf("%s %v error = %v, expected failure %t", test._type, test.raw, err, test.fails)
+			continue
+		}
+		if test.fails {
+			continue
+		}
+		if converted.sql != test.sql {
+			t.Errorf("%s %v SQL = %s, expected %s", test._type, test.raw, converted.sql, test.sql)
+		}
+		if converted.goCode != test.goCode {
+			t.Errorf("%s %v Go = %s, expected %s", test._type, test.raw, converted.goCode, test.goCode)
+		}
+	}
+}
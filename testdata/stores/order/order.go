@@ -0,0 +1,19 @@
+package order
+
+import "time"
+
+// @synthesize-naming snake
+// @synthesize
+type Order struct {
+	id                 uint
+	createdByID        string
+	updatedByID        *string
+	createdAt          time.Time
+	updatedAt          *time.Time
+	createdByFirstName *string
+	createdBySurname   *string
+	updatedByFirstName *string
+	updatedBySurname   *string
+	reference          string
+	lines              []*OrderLine // @synthesize-no-db-field @synthesize-has-many orderID
+}
@@ -0,0 +1,28 @@
+package order
+
+import "time"
+
+// @synthesize
+type OrderLine struct {
+	id                 uint
+	createdByID        string
+	updatedByID        *string
+	createdAt          time.Time
+	updatedAt          *time.Time
+	createdByFirstName *string
+	createdBySurname   *string
+	updatedByFirstName *string
+	updatedBySurname   *string
+	orderID            uint // @synthesize-belongs-to Order
+	amount             uint16
+}
+
+// TableName returns the table name that belongs to the current model.
+func (o *OrderLine) TableName() string {
+	return "OrderLine"
+}
+
+// TableAlias returns the unique resolved table alias for use in queries.
+func (o *OrderLine) TableAlias() string {
+	return "ol"
+}
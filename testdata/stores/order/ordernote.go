@@ -0,0 +1,19 @@
+package order
+
+// @synthesize
+type orderNote struct {
+	id        uint   // @synthesize-json -
+	orderID   uint   // @synthesize-json -
+	text      string // @synthesize-json -
+	replyToID *uint  // @synthesize-belongs-to orderNote @synthesize-json -
+}
+
+// TableName returns the table name that belongs to the current model.
+func (o *orderNote) TableName() string {
+	return "OrderNote"
+}
+
+// TableAlias returns the unique resolved table alias for use in queries.
+func (o *orderNote) TableAlias() string {
+	return "n"
+}
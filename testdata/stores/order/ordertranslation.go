@@ -0,0 +1,30 @@
+package order
+
+import "time"
+
+// @synthesize
+type OrderTranslation struct {
+	id                 uint
+	createdByID        string
+	updatedByID        *string
+	createdAt          time.Time
+	updatedAt          *time.Time
+	createdByFirstName *string
+	createdBySurname   *string
+	updatedByFirstName *string
+	updatedBySurname   *string
+	orderID            uint // @synthesize-belongs-to Order
+	language           uint16
+	field              uint16
+	value              string
+}
+
+// TableName returns the table name that belongs to the current model.
+func (o *OrderTranslation) TableName() string {
+	return "OrderTranslation"
+}
+
+// TableAlias returns the unique resolved table alias for use in queries.
+func (o *OrderTranslation) TableAlias() string {
+	return "ot"
+}
@@ -0,0 +1,12 @@
+package order
+
+import (
+	"github.com/espal-digital-development/espal-core/database"
+)
+
+// OrdersStore data store.
+type OrdersStore struct {
+	selecterDatabase database.Database
+	inserterDatabase database.Database
+	deletorDatabase  database.Database
+}
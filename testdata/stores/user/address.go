@@ -0,0 +1,28 @@
+package user
+
+import "time"
+
+// @synthesize
+type Address struct {
+	id                 string
+	createdByID        string
+	updatedByID        *string
+	createdAt          time.Time
+	updatedAt          *time.Time
+	createdByFirstName *string
+	createdBySurname   *string
+	updatedByFirstName *string
+	updatedBySurname   *string
+	userID             string // @synthesize-belongs-to User
+	street             string
+}
+
+// TableName returns the table name that belongs to the current model.
+func (a *Address) TableName() string {
+	return "Address"
+}
+
+// TableAlias returns the unique resolved table alias for use in queries.
+func (a *Address) TableAlias() string {
+	return "a"
+}
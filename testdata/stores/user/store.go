@@ -0,0 +1,13 @@
+package user
+
+import (
+	"github.com/espal-digital-development/espal-core/database"
+)
+
+// UsersStore data store.
+type UsersStore struct {
+	selecterDatabase database.Database
+	updaterDatabase  database.Database
+	deletorDatabase  database.Database
+	inserterDatabase database.Database
+}
@@ -0,0 +1,28 @@
+package user
+
+import "time"
+
+// @synthesize-track-changes
+// @synthesize
+type User struct {
+	changedFields      map[UserField]bool
+	version            uint
+	id                 string
+	createdByID        string
+	updatedByID        *string
+	createdAt          time.Time
+	updatedAt          *time.Time
+	deletedAt          *time.Time
+	createdByFirstName *string
+	createdBySurname   *string
+	updatedByFirstName *string
+	updatedBySurname   *string
+	email              string // Primary e-mail address. @synthesize-json emailAddress @synthesize-validate required,max=255,email
+	firstName          *string
+	dateOfBirth        *time.Time
+	avatar             []byte
+	priority           uint16     // @synthesize-validate min=1,max=10
+	isDirty            bool       // @synthesize-no-db-field @synthesize-json -
+	sessionCount       uint       // Sessions opened since the fetch. @synthesize-no-db-field
+	addresses          []*Address // @synthesize-no-db-field @synthesize-has-many userID
+}
@@ -0,0 +1,20 @@
+package user
+
+import "time"
+
+// @synthesize
+type UserTranslation struct {
+	id                 string
+	createdByID        string
+	updatedByID        *string
+	createdAt          time.Time
+	updatedAt          *time.Time
+	createdByFirstName *string
+	createdBySurname   *string
+	updatedByFirstName *string
+	updatedBySurname   *string
+	userID             string // @synthesize-belongs-to User
+	language           uint16
+	field              uint16
+	value              string
+}
@@ -0,0 +1,112 @@
+package typescript
+
+import (
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/espal-digital-development/espal-store-synthesizer/packages"
+	"github.com/espal-digital-development/system/permissions"
+	"github.com/juju/errors"
+)
+
+// TypeScript package object.
+type TypeScript struct {
+	path         string
+	typeNames    map[*packages.Entity]string
+	reIdentifier *regexp.Regexp
+}
+
+// Build writes the definitions file with an interface per entity.
+func (t *TypeScript) Build(pkgs []*packages.Package) error {
+	t.typeNames = packages.TypeNames(pkgs)
+	output := &strings.Builder{}
+	output.WriteString("// Code generated by espal-store-synthesizer. DO NOT EDIT.\n")
+	for _, pkg := range pkgs {
+		for _, entity := range pkg.AllEntities() {
+			t.writeInterface(output, entity)
+		}
+	}
+	return errors.Trace(ioutil.WriteFile(t.path, []byte(output.String()), permissions.UserReadWrite))
+}
+
+// writeInterface writes the interface of the entity's JSON.
+func (t *TypeScript) writeInterface(output *strings.Builder, entity *packages.Entity) {
+	output.WriteString("\n")
+	output.WriteString("/** " + entity.Name() + " of the " + entity.PackageName() + " store. */\n")
+	output.WriteString("export interface " + t.typeNames[entity] + " {\n")
+	for _, property := range entity.JSONProperties() {
+		description := property.Description()
+		switch property.BaseType() {
+		case "time.Time":
+			description = strings.TrimSpace(description + " RFC 3339 time.")
+		case "time.Duration":
+			description = strings.TrimSpace(description + " Duration in nanoseconds.")
+		case "[]byte":
+			description = strings.TrimSpace(description + " Base64 encoded.")
+		}
+		if description != "" {
+			output.WriteString("  /** " + strings.ReplaceAll(description, "*/", "*\\/") + " */\n")
+		}
+		output.WriteString("  ")
+		if entity.IsJSONReadOnly(property) {
+			output.WriteString("readonly ")
+		}
+		name := property.JSONName()
+		if !t.reIdentifier.MatchString(name) {
+			name = strconv.Quote(name)
+		}
+		output.WriteString(name + ": " + t.propertyType(entity, property) + ";\n")
+	}
+	output.WriteString("}\n")
+}
+
+// propertyType returns the TypeScript type of the property as the generated MarshalJSON encodes it.
+func (t *TypeScript) propertyType(entity *packages.Entity, property *packages.Property) string {
+	for _, relation := range entity.Relations() {
+		if relation.Property() == property && relation.TargetEntity() != nil &&
+			relation.Kind() == packages.RelationHasMany {
+			return t.typeNames[relation.TargetEntity()] + "[] | null"
+		}
+	}
+	typeName := goType(property.BaseType())
+	// Pointers and nil slices are encoded as null
+	if property.IsPointer() || strings.HasPrefix(property.Type(), "[]") {
+		typeName += " | null"
+	}
+	return typeName
+}
+
+// goType returns the TypeScript type of the Go type's JSON.
+func goType(name string) string {
+	switch name {
+	case "string", "time.Time", "[]byte":
+		return "string"
+	case "bool":
+		return "boolean"
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64",
+		"float32", "float64", "time.Duration":
+		return "number"
+	}
+	if strings.HasPrefix(name, "[]") {
+		element := goType(strings.TrimPrefix(strings.TrimPrefix(name, "[]"), "*"))
+		if strings.Contains(element, " ") {
+			element = "(" + element + ")"
+		}
+		return element + "[]"
+	}
+	// Types from other packages can be anything
+	return "unknown"
+}
+
+// New returns a new instance of TypeScript that writes the definitions to the path.
+func New(path string) (*TypeScript, error) {
+	if path == "" {
+		return nil, errors.New("the TypeScript definitions need a path")
+	}
+	return &TypeScript{
+		path:         path,
+		reIdentifier: regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`),
+	}, nil
+}
@@ -0,0 +1,91 @@
+package typescript
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/espal-digital-development/espal-store-synthesizer/packages"
+	"github.com/juju/errors"
+)
+
+const expectedUser = `
+/** User of the user store. */
+export interface User {
+  version: number;
+  id: string;
+  readonly createdByID: string;
+  readonly updatedByID: string | null;
+  /** RFC 3339 time. */
+  readonly createdAt: string;
+  /** RFC 3339 time. */
+  readonly updatedAt: string | null;
+  /** RFC 3339 time. */
+  deletedAt: string | null;
+  readonly createdByFirstName: string | null;
+  readonly createdBySurname: string | null;
+  readonly updatedByFirstName: string | null;
+  readonly updatedBySurname: string | null;
+  /** Primary e-mail address. */
+  emailAddress: string;
+  firstName: string | null;
+  /** RFC 3339 time. */
+  dateOfBirth: string | null;
+  /** Base64 encoded. */
+  avatar: string | null;
+  priority: number;
+  /** Sessions opened since the fetch. */
+  sessionCount: number;
+  addresses: Address[] | null;
+}
+`
+
+func TestBuild(t *testing.T) {
+	pkg := packages.New(&packages.Config{NamingStrategy: packages.NamingAsIs})
+	if err := pkg.BuildMetaData("../testdata/stores/user"); err != nil {
+		t.Fatal(errors.ErrorStack(err))
+	}
+	typeScript, err := New(t.TempDir() + "/stores.d.ts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := typeScript.Build([]*packages.Package{pkg}); err != nil {
+		t.Fatal(errors.ErrorStack(err))
+	}
+	output, err := ioutil.ReadFile(typeScript.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(output), expectedUser) {
+		t.Errorf("Expected the User interface%s\ngot\n%s", expectedUser, output)
+	}
+	for _, expected := range []string{"export interface Address {\n", "export interface UserTranslation {\n"} {
+		if !strings.Contains(string(output), expected) {
+			t.Errorf("Expected %s", expected)
+		}
+	}
+}
+
+func TestGoType(t *testing.T) {
+	tests := []struct {
+		goType   string
+		expected string
+	}{
+		{"string", "string"},
+		{"bool", "boolean"},
+		{"uint16", "number"},
+		{"time.Time", "string"},
+		{"time.Duration", "number"},
+		{"[]byte", "string"},
+		{"[]string", "string[]"},
+		{"[]*int64", "number[]"},
+		{"[][]bool", "boolean[][]"},
+		{"[]uuid.UUID", "unknown[]"},
+		{"sql.NullString", "unknown"},
+	}
+	for _, test := range tests {
+		if typeName := goType(test.goType); typeName != test.expected {
+			t.Errorf("Expected %s for %s, got %s", test.expected, test.goType, typeName)
+		}
+	}
+}